@@ -0,0 +1,108 @@
+package analysis
+
+import "blackjack/internal/data"
+
+// ActionEV is the expectation of an action per initial bet of the hand.
+type ActionEV struct {
+	Action data.Action
+	EV     float64
+}
+
+// ActionEVs returns the exact EV of every action open to player against
+// upcard, with shoe holding the cards the player has not seen (including the
+// hole card). The dealer has already peeked, so the hole card cannot give a
+// blackjack. After the first decision the hand is played perfectly for the
+// composition, not by the charts. hands is how many hands the player holds,
+// which limits further splits. Actions come back in data.Action order.
+func (c *Calculator) ActionEVs(player *data.Hand, upcard data.Card, shoe Shoe, hands int) []ActionEV {
+	var h hand
+	for _, card := range player.Cards() {
+		h.add(CardValue(card))
+	}
+	h.split = player.IsSplit()
+	return c.actionEVs(h, CardValue(upcard), shoe, hands)
+}
+
+func (c *Calculator) actionEVs(h hand, up int, shoe Shoe, hands int) []ActionEV {
+	budget := c.rules.MaxSplitHands - hands + 1
+
+	evs := []ActionEV{
+		{Action: data.ActionHit, EV: c.hitEV(shoe, up, h)},
+		{Action: data.ActionStand, EV: c.standEV(shoe, up, h.value())},
+	}
+	if c.canDouble(h) {
+		evs = append(evs, ActionEV{Action: data.ActionDouble, EV: c.doubleEV(shoe, up, h)})
+	}
+	if h.pair && budget > 1 {
+		evs = append(evs, ActionEV{Action: data.ActionSplit, EV: c.bestSplitEV(shoe, up, h.first, budget)})
+	}
+	if c.rules.Surrender && h.cards == 2 && !h.split {
+		evs = append(evs, ActionEV{Action: data.ActionSurrender, EV: -0.5})
+	}
+	return evs
+}
+
+// Best returns the action with the highest EV.
+func Best(evs []ActionEV) ActionEV {
+	best := evs[0]
+	for _, ev := range evs[1:] {
+		if ev.EV > best.EV {
+			best = ev
+		}
+	}
+	return best
+}
+
+func (c *Calculator) canDouble(h hand) bool {
+	return h.cards == 2 && (!h.split || c.rules.DoubleAfterSplit)
+}
+
+// hitEV takes one card and then stands or hits on, whichever is better.
+func (c *Calculator) hitEV(shoe Shoe, up int, h hand) float64 {
+	return c.drawEV(shoe, up, h, func(next Shoe, nh hand) float64 {
+		return c.bestEV(next, up, nh, 1)
+	})
+}
+
+func (c *Calculator) doubleEV(shoe Shoe, up int, h hand) float64 {
+	return 2 * c.drawEV(shoe, up, h, func(next Shoe, nh hand) float64 {
+		return c.standEV(next, up, nh.value())
+	})
+}
+
+// bestEV plays h perfectly for the composition. Surrender is left out
+// because bestEV only ever sees hands after the first decision.
+func (c *Calculator) bestEV(shoe Shoe, up int, h hand, budget int) float64 {
+	key := evKey{shoe: shoe, up: up, hand: h, budget: budget}
+	if ev, ok := c.bestMemo[key]; ok {
+		return ev
+	}
+	ev := c.standEV(shoe, up, h.value())
+	if h.value() < 21 || h.soft() {
+		ev = max(ev, c.hitEV(shoe, up, h))
+	}
+	if c.canDouble(h) {
+		ev = max(ev, c.doubleEV(shoe, up, h))
+	}
+	if h.pair && budget > 1 {
+		ev = max(ev, c.bestSplitEV(shoe, up, h.first, budget))
+	}
+	c.bestMemo[key] = ev
+	return ev
+}
+
+// bestSplitEV is splitEV with each split hand played perfectly.
+func (c *Calculator) bestSplitEV(shoe Shoe, up, card, budget int) float64 {
+	ev := 0.0
+	for _, share := range [2]int{(budget + 1) / 2, budget / 2} {
+		start := hand{split: true}
+		start.add(card)
+		ev += c.drawEV(shoe, up, start, func(next Shoe, nh hand) float64 {
+			if c.oneCard(card) {
+				return c.standEV(next, up, nh.value())
+			}
+			return c.bestEV(next, up, nh, share)
+		})
+	}
+	return ev
+}
@@ -0,0 +1,280 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"blackjack/internal/data"
+)
+
+func newTestCalculator(t *testing.T, rules data.Rules) *Calculator {
+	t.Helper()
+	calc, err := NewCalculator(rules)
+	if err != nil {
+		t.Fatalf("NewCalculator: %v", err)
+	}
+	return calc
+}
+
+func TestDealerProbsSumToOne(t *testing.T) {
+	calc := newTestCalculator(t, data.DefaultRules())
+	shoe := NewShoe(6)
+	for up := 1; up <= 10; up++ {
+		for _, peek := range []bool{false, true} {
+			probs := calc.dealer(shoe.Without(up), up, peek)
+			sum := 0.0
+			for _, p := range probs {
+				sum += p
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("upcard %d peek %v: probabilities sum to %v", up, peek, sum)
+			}
+			if peek && probs[DealerBlackjack] != 0 {
+				t.Errorf("upcard %d: blackjack after peek has probability %v", up, probs[DealerBlackjack])
+			}
+		}
+	}
+}
+
+func TestDealerBustChance(t *testing.T) {
+	calc := newTestCalculator(t, data.DefaultRules())
+	six := calc.dealer(NewShoe(6).Without(6), 6, true)[DealerBust]
+	ten := calc.dealer(NewShoe(6).Without(10), 10, true)[DealerBust]
+	if six < 0.40 || six > 0.45 {
+		t.Errorf("bust chance under a 6 = %.4f, want about 0.42", six)
+	}
+	if ten >= six {
+		t.Errorf("bust chance under a 10 (%.4f) should be below a 6 (%.4f)", ten, six)
+	}
+}
+
+func TestEdgeDefaultRules(t *testing.T) {
+	edge, err := Edge(data.DefaultRules())
+	if err != nil {
+		t.Fatalf("Edge: %v", err)
+	}
+	// Six decks H17 DAS with one card to split aces, published at 0.60%.
+	if edge > -0.005 || edge < -0.007 {
+		t.Errorf("edge = %.4f%%, want a house edge between 0.5%% and 0.7%%", edge*100)
+	}
+}
+
+func TestRuleEffects(t *testing.T) {
+	if testing.Short() {
+		t.Skip("computes several full rule sets")
+	}
+	rules := data.DefaultRules()
+	rules.Decks = 2
+	effects, err := RuleEffects(rules)
+	if err != nil {
+		t.Fatalf("RuleEffects: %v", err)
+	}
+	for _, effect := range effects {
+		switch effect.Name {
+		case "S17 vs H17", "DAS vs no DAS", "Hit split aces vs one card", "Late surrender vs none", "5-card Charlie vs none", "1 vs 2 decks":
+			if effect.Effect <= 0 {
+				t.Errorf("%s: want a gain for the player", effect)
+			}
+		case "8 vs 2 decks":
+			if effect.Effect >= 0 {
+				t.Errorf("%s: want a loss for the player", effect)
+			}
+		}
+	}
+}
+
+func TestNewCalculatorRejectsBadRules(t *testing.T) {
+	rules := data.DefaultRules()
+	rules.Decks = 0
+	if _, err := NewCalculator(rules); err == nil {
+		t.Error("expected an error for zero decks")
+	}
+}
+
+func newAnalysisHand(cards ...data.Card) *data.Hand {
+	hand := data.NewHand()
+	for _, card := range cards {
+		hand.AddCard(card)
+	}
+	return hand
+}
+
+func evFor(t *testing.T, evs []ActionEV, action data.Action) float64 {
+	t.Helper()
+	for _, ev := range evs {
+		if ev.Action == action {
+			return ev.EV
+		}
+	}
+	t.Fatalf("no EV for %v in %v", action, evs)
+	return 0
+}
+
+func TestActionEVs(t *testing.T) {
+	rules := data.DefaultRules()
+	rules.Surrender = true
+	calc := newTestCalculator(t, rules)
+	shoe := NewShoe(6)
+
+	// 16 v 10: surrender beats hit, which beats stand.
+	sixteen := newAnalysisHand(data.Card{Suit: data.Spades, Rank: data.Ten}, data.Card{Suit: data.Hearts, Rank: data.Six})
+	ten := data.Card{Suit: data.Clubs, Rank: data.Ten}
+	evs := calc.ActionEVs(sixteen, ten, shoe.Without(10, 6, 10), 1)
+	if best := Best(evs); best.Action != data.ActionSurrender {
+		t.Errorf("16 v 10: best = %v, want surrender", best)
+	}
+	if hit, stand := evFor(t, evs, data.ActionHit), evFor(t, evs, data.ActionStand); hit <= stand {
+		t.Errorf("16 v 10: hit %.4f should beat stand %.4f", hit, stand)
+	}
+
+	// 11 v 6 doubles; the EV must beat hitting.
+	eleven := newAnalysisHand(data.Card{Suit: data.Spades, Rank: data.Six}, data.Card{Suit: data.Hearts, Rank: data.Five})
+	six := data.Card{Suit: data.Clubs, Rank: data.Six}
+	evs = calc.ActionEVs(eleven, six, shoe.Without(6, 5, 6), 1)
+	if best := Best(evs); best.Action != data.ActionDouble {
+		t.Errorf("11 v 6: best = %v, want double", best)
+	}
+
+	// 8,8 v 6 splits, and a tens-rich shoe makes 16 v 10 a stand.
+	eights := newAnalysisHand(data.Card{Suit: data.Spades, Rank: data.Eight}, data.Card{Suit: data.Hearts, Rank: data.Eight})
+	evs = calc.ActionEVs(eights, six, shoe.Without(8, 8, 6), 1)
+	if best := Best(evs); best.Action != data.ActionSplit {
+		t.Errorf("8,8 v 6: best = %v, want split", best)
+	}
+	var rich Shoe
+	rich[10] = 20
+	rich[5] = 1
+	rich[2] = 1
+	evs = calc.ActionEVs(sixteen, ten, rich, 1)
+	if hit, stand := evFor(t, evs, data.ActionHit), evFor(t, evs, data.ActionStand); stand <= hit {
+		t.Errorf("16 v 10 in a ten-rich shoe: stand %.4f should beat hit %.4f", stand, hit)
+	}
+}
+
+func BenchmarkActionEVsPairOfTwos(b *testing.B) {
+	twos := newAnalysisHand(data.Card{Suit: data.Spades, Rank: data.Two}, data.Card{Suit: data.Hearts, Rank: data.Two})
+	upcard := data.Card{Suit: data.Clubs, Rank: data.Seven}
+	shoe := NewShoe(6).Without(2, 2, 7)
+	for range b.N {
+		calc, _ := NewCalculator(data.DefaultRules())
+		calc.ActionEVs(twos, upcard, shoe, 1)
+	}
+}
+
+func TestSplitAcesTakeOneCard(t *testing.T) {
+	rules := data.DefaultRules()
+	oneCard := newTestCalculator(t, rules)
+	rules.HitSplitAces = true
+	hitting := newTestCalculator(t, rules)
+
+	aces := newAnalysisHand(data.Card{Suit: data.Spades, Rank: data.Ace}, data.Card{Suit: data.Hearts, Rank: data.Ace})
+	six := data.Card{Suit: data.Clubs, Rank: data.Six}
+	shoe := NewShoe(6).Without(1, 6, 1)
+	one := evFor(t, oneCard.ActionEVs(aces, six, shoe, 1), data.ActionSplit)
+	hit := evFor(t, hitting.ActionEVs(aces, six, shoe, 1), data.ActionSplit)
+	if one <= 0 || one >= hit {
+		t.Errorf("split aces v 6: one card %.4f, drawing %.4f; want a smaller gain for one card", one, hit)
+	}
+	if oneCard.splitEV(shoe, 6, 1, 4) >= hitting.splitEV(shoe, 6, 1, 4) {
+		t.Error("basic strategy split aces should be worth less on one card")
+	}
+}
+
+func TestDealerTableSoft17(t *testing.T) {
+	h17 := newTestCalculator(t, data.DefaultRules()).DealerTable(false)
+	rules := data.DefaultRules()
+	rules.DealerHitsSoft17 = false
+	s17 := newTestCalculator(t, rules).DealerTable(false)
+
+	// Hitting soft 17 trades some 17s for busts and higher totals.
+	if h17[6][DealerBust] <= s17[6][DealerBust] {
+		t.Errorf("bust under a 6: H17 %.4f should exceed S17 %.4f", h17[6][DealerBust], s17[6][DealerBust])
+	}
+	if h17[6][Dealer17] >= s17[6][Dealer17] {
+		t.Errorf("17 under a 6: H17 %.4f should be below S17 %.4f", h17[6][Dealer17], s17[6][Dealer17])
+	}
+	// A dealer showing a 10 can never hold soft 17.
+	if h17[10] != s17[10] {
+		t.Errorf("10 upcard: H17 %v differs from S17 %v", h17[10], s17[10])
+	}
+	if got := h17[10][DealerBlackjack]; math.Abs(got-24.0/311) > 1e-9 {
+		t.Errorf("blackjack under a 10 = %.6f, want %.6f", got, 24.0/311)
+	}
+}
+
+func TestBustChanceAndStandOdds(t *testing.T) {
+	shoe := NewShoe(1).Without(10, 6, 10)
+	sixteen := newAnalysisHand(data.Card{Suit: data.Spades, Rank: data.Ten}, data.Card{Suit: data.Hearts, Rank: data.Six})
+	// Sixes through tens bust 16: 3 sixes, 4 each of 7-9 and 14 tens.
+	if got, want := BustChance(sixteen, shoe), 29.0/49; math.Abs(got-want) > 1e-9 {
+		t.Errorf("bust chance on 16 = %.4f, want %.4f", got, want)
+	}
+	soft := newAnalysisHand(data.Card{Suit: data.Spades, Rank: data.Ace}, data.Card{Suit: data.Hearts, Rank: data.Six})
+	if got := BustChance(soft, shoe); got != 0 {
+		t.Errorf("soft 17 cannot bust, got %.4f", got)
+	}
+
+	calc := newTestCalculator(t, data.DefaultRules())
+	upcard := data.Card{Suit: data.Clubs, Rank: data.Ten}
+	odds := calc.StandOdds(shoe, upcard, 16)
+	if odds.Push != 0 {
+		t.Errorf("16 cannot push, got %.4f", odds.Push)
+	}
+	if sum := odds.Win + odds.Push + odds.Lose; math.Abs(sum-1) > 1e-9 {
+		t.Errorf("stand odds sum to %v", sum)
+	}
+	if twenty := calc.StandOdds(shoe, upcard, 20); twenty.Win <= odds.Win || twenty.Push == 0 {
+		t.Errorf("20 should win more often than 16 and can push: %+v vs %+v", twenty, odds)
+	}
+}
+
+func TestReviewReplaysActualCards(t *testing.T) {
+	rules := data.DefaultRules()
+	game, err := data.NewGameWithRules(rules, []data.PlayerConfig{{Name: "Alice", Bankroll: 1000}})
+	if err != nil {
+		t.Fatalf("NewGameWithRules: %v", err)
+	}
+	game.Seed(7)
+	player := game.Players()[0]
+	calc := newTestCalculator(t, rules)
+
+	for round := 0; round < 20; round++ {
+		before := player.Bankroll()
+		if err := game.StartRound(map[string]int{"Alice": 10}); err != nil {
+			t.Fatalf("StartRound: %v", err)
+		}
+		if err := game.DealInitialCards(); err != nil {
+			t.Fatalf("DealInitialCards: %v", err)
+		}
+		if !game.ReadyForDealer() {
+			if err := game.Stand(player); err != nil {
+				t.Fatalf("Stand: %v", err)
+			}
+			game.ReadyForDealer()
+		}
+		if err := game.DealerPlay(); err != nil {
+			t.Fatalf("DealerPlay: %v", err)
+		}
+		if _, err := game.SettleRound(); err != nil {
+			t.Fatalf("SettleRound: %v", err)
+		}
+
+		steps, err := calc.Review(game, player)
+		if err != nil {
+			t.Fatalf("Review: %v", err)
+		}
+		if len(steps) != len(game.Decisions()) {
+			t.Fatalf("round %d: %d review steps for %d decisions", round, len(steps), len(game.Decisions()))
+		}
+		for _, step := range steps {
+			if len(step.Alternatives) < 2 {
+				t.Errorf("round %d: expected at least hit and stand, got %v", round, step.Alternatives)
+			}
+			chosen := step.Chosen()
+			if want := float64(player.Bankroll()-before) / 10; chosen.Result != want {
+				t.Errorf("round %d: replayed stand = %v, actual result %v", round, chosen.Result, want)
+			}
+		}
+		game.PrepareNextRound()
+	}
+}
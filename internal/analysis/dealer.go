@@ -0,0 +1,160 @@
+package analysis
+
+import (
+	"fmt"
+	"io"
+
+	"blackjack/internal/data"
+)
+
+// Dealer final results, in the order DealerProbs stores them.
+const (
+	Dealer17 = iota
+	Dealer18
+	Dealer19
+	Dealer20
+	Dealer21
+	DealerBlackjack
+	DealerBust
+	dealerResults
+)
+
+// DealerProbs holds the chance of each dealer final result.
+type DealerProbs [dealerResults]float64
+
+type dealerKey struct {
+	shoe Shoe
+	up   int
+	peek bool
+}
+
+// DealerOutcomes returns the dealer's final result probabilities for upcard
+// drawing from shoe, which must not include the upcard. With peek set the
+// dealer has checked for blackjack and does not have one.
+func (c *Calculator) DealerOutcomes(shoe Shoe, upcard data.Card, peek bool) DealerProbs {
+	return c.dealer(shoe, CardValue(upcard), peek)
+}
+
+// DealerTable returns the dealer's result probabilities off the top of a
+// full shoe for each upcard, indexed by shoe value with aces as 1.
+func (c *Calculator) DealerTable(peek bool) [11]DealerProbs {
+	var table [11]DealerProbs
+	shoe := NewShoe(c.rules.Decks)
+	for up := 1; up <= 10; up++ {
+		table[up] = c.dealer(shoe.Without(up), up, peek)
+	}
+	return table
+}
+
+// ReportDealer prints the dealer outcome table for rules.
+func ReportDealer(w io.Writer, rules data.Rules, peek bool) error {
+	calc, err := NewCalculator(rules)
+	if err != nil {
+		return err
+	}
+	table := calc.DealerTable(peek)
+	note := "before the dealer checks for blackjack"
+	if peek {
+		note = "after the dealer peeks and has no blackjack"
+	}
+	fmt.Fprintf(w, "Dealer outcomes for %s, %s\n\n", rules, note)
+	fmt.Fprintf(w, "%-4s", "Up")
+	for _, name := range dealerResultNames {
+		fmt.Fprintf(w, "%8s", name)
+	}
+	fmt.Fprintln(w)
+	// Upcards run 2 through ace, the order strategy charts use.
+	for _, up := range [10]int{2, 3, 4, 5, 6, 7, 8, 9, 10, 1} {
+		label := fmt.Sprint(up)
+		if up == 1 {
+			label = "A"
+		}
+		fmt.Fprintf(w, "%-4s", label)
+		for _, p := range table[up] {
+			fmt.Fprintf(w, "%7.2f%%", p*100)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+var dealerResultNames = [dealerResults]string{"17", "18", "19", "20", "21", "BJ", "Bust"}
+
+// dealer returns the dealer's final result probabilities for upcard up
+// drawing from shoe. With peek set the dealer is known not to hold
+// blackjack, so the hole card can never complete one.
+func (c *Calculator) dealer(shoe Shoe, up int, peek bool) DealerProbs {
+	key := dealerKey{shoe: shoe, up: up, peek: peek}
+	if probs, ok := c.dealerMemo[key]; ok {
+		return probs
+	}
+	var probs DealerProbs
+	hole := 0
+	if peek {
+		// The hole card cannot be the one that makes blackjack.
+		switch up {
+		case 1:
+			hole = 10
+		case 10:
+			hole = 1
+		}
+	}
+	c.dealerDraw(&probs, &shoe, shoe.Total(), up, up == 1, 1, hole, 1)
+	c.dealerMemo[key] = probs
+	return probs
+}
+
+// dealerDraw deals to a dealer holding cards totalling hard, drawing from
+// shoe, which holds total cards and is restored before returning. excluded
+// is a value the next card is known not to be, or 0.
+func (c *Calculator) dealerDraw(probs *DealerProbs, shoe *Shoe, total, hard int, ace bool, cards, excluded int, weight float64) {
+	value := hard
+	soft := ace && hard+10 <= 21
+	if soft {
+		value += 10
+	}
+	switch {
+	case cards == 2 && value == 21:
+		probs[DealerBlackjack] += weight
+		return
+	case value > 21:
+		probs[DealerBust] += weight
+		return
+	case cards >= 2 && !data.DealerShouldHit(value, soft, c.rules.DealerHitsSoft17):
+		probs[Dealer17+value-17] += weight
+		return
+	}
+
+	remaining := total - 1
+	if excluded != 0 {
+		total -= shoe[excluded]
+	}
+	if total <= 0 {
+		// An exhausted shoe cannot happen at a real table; stand on what
+		// the dealer has so the probabilities still sum to one.
+		probs[Dealer17+max(0, min(value, 21)-17)] += weight
+		return
+	}
+	for v := 1; v <= 10; v++ {
+		n := shoe[v]
+		if n == 0 || v == excluded {
+			continue
+		}
+		p := weight * float64(n) / float64(total)
+		shoe[v]--
+		c.dealerDraw(probs, shoe, remaining, hard+v, ace || v == 1, cards+1, 0, p)
+		shoe[v]++
+	}
+}
+
+// blackjackChance is the chance the hole card gives the dealer blackjack.
+func blackjackChance(shoe Shoe, up int) float64 {
+	switch up {
+	case 1:
+		return shoe.Prob(10)
+	case 10:
+		return shoe.Prob(1)
+	default:
+		return 0
+	}
+}
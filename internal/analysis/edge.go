@@ -0,0 +1,151 @@
+package analysis
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"blackjack/internal/data"
+)
+
+// Edge computes the basic strategy expectation for rules off the top of the
+// shoe.
+func Edge(rules data.Rules) (float64, error) {
+	calc, err := NewCalculator(rules)
+	if err != nil {
+		return 0, err
+	}
+	return calc.Edge(), nil
+}
+
+// RuleEffects prices each rule by recomputing the edge with only that rule
+// changed from rules. Effects are stated from the player's side, so a
+// positive value means the first named rule is better for the player.
+func RuleEffects(rules data.Rules) ([]RuleEffect, error) {
+	effects, _, err := ruleEffects(rules)
+	return effects, err
+}
+
+// ruleEffects also returns the edge of every rule set it computed, which
+// always includes rules.
+func ruleEffects(rules data.Rules) ([]RuleEffect, map[data.Rules]float64, error) {
+	type variant struct {
+		name   string
+		better func(*data.Rules)
+		worse  func(*data.Rules)
+	}
+	variants := []variant{
+		{
+			name:   "S17 vs H17",
+			better: func(r *data.Rules) { r.DealerHitsSoft17 = false },
+			worse:  func(r *data.Rules) { r.DealerHitsSoft17 = true },
+		},
+		{
+			name:   "DAS vs no DAS",
+			better: func(r *data.Rules) { r.DoubleAfterSplit = true },
+			worse:  func(r *data.Rules) { r.DoubleAfterSplit = false },
+		},
+		{
+			name:   "Hit split aces vs one card",
+			better: func(r *data.Rules) { r.HitSplitAces = true },
+			worse:  func(r *data.Rules) { r.HitSplitAces = false },
+		},
+		{
+			name:   "Late surrender vs none",
+			better: func(r *data.Rules) { r.Surrender = true },
+			worse:  func(r *data.Rules) { r.Surrender = false },
+		},
+		{
+			name:   "Resplit to 4 hands vs split once",
+			better: func(r *data.Rules) { r.MaxSplitHands = 4 },
+			worse:  func(r *data.Rules) { r.MaxSplitHands = 2 },
+		},
+		{
+			name:   "5-card Charlie vs none",
+			better: func(r *data.Rules) { r.Charlie = 5 },
+			worse:  func(r *data.Rules) { r.Charlie = 0 },
+		},
+	}
+	for _, decks := range []int{1, 2, 6, 8} {
+		if decks == rules.Decks {
+			continue
+		}
+		variants = append(variants, variant{
+			name:   fmt.Sprintf("%d vs %d decks", decks, rules.Decks),
+			better: func(r *data.Rules) { r.Decks = decks },
+			worse:  func(*data.Rules) {},
+		})
+	}
+
+	// Each distinct rule set is computed once, in parallel; several
+	// variants share a rule set with rules itself.
+	edges := map[data.Rules]float64{rules: 0}
+	for _, v := range variants {
+		better, worse := rules, rules
+		v.better(&better)
+		v.worse(&worse)
+		edges[better] = 0
+		edges[worse] = 0
+	}
+	if err := computeEdges(edges); err != nil {
+		return nil, nil, err
+	}
+
+	effects := make([]RuleEffect, 0, len(variants))
+	for _, v := range variants {
+		better, worse := rules, rules
+		v.better(&better)
+		v.worse(&worse)
+		effects = append(effects, RuleEffect{Name: v.name, Effect: edges[better] - edges[worse]})
+	}
+	return effects, edges, nil
+}
+
+// computeEdges fills in the edge for every rule set keyed in edges.
+func computeEdges(edges map[data.Rules]float64) error {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	pending := make([]data.Rules, 0, len(edges))
+	for rules := range edges {
+		pending = append(pending, rules)
+	}
+	for _, rules := range pending {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			edge, err := Edge(rules)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			edges[rules] = edge
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// Report prints the exact edge for rules and the value of each rule.
+func Report(w io.Writer, rules data.Rules) error {
+	effects, edges, err := ruleEffects(rules)
+	if err != nil {
+		return err
+	}
+	edge := edges[rules]
+	fmt.Fprintf(w, "Rules:              %s\n", rules)
+	fmt.Fprintf(w, "Player advantage:   %+.3f%%\n", edge*100)
+	fmt.Fprintf(w, "House edge:         %.3f%%\n", -edge*100)
+	if estimate, ok := rules.EstimatedHouseEdge(); ok {
+		fmt.Fprintf(w, "Published estimate: %.3f%%\n", estimate*100)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Rule effects (player's side):")
+	for _, effect := range effects {
+		fmt.Fprintf(w, "  %s\n", effect)
+	}
+	return nil
+}
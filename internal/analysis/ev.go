@@ -0,0 +1,261 @@
+package analysis
+
+import (
+	"fmt"
+
+	"blackjack/internal/data"
+)
+
+// Calculator computes exact expectations for one rule set. Results are
+// memoized by shoe composition, so a Calculator is not safe for concurrent
+// use.
+type Calculator struct {
+	rules   data.Rules
+	advisor *data.Advisor
+
+	dealerMemo map[dealerKey]DealerProbs
+	evMemo     map[evKey]float64
+	bestMemo   map[evKey]float64
+}
+
+func NewCalculator(rules data.Rules) (*Calculator, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	if rules.Variant != data.Standard {
+		return nil, fmt.Errorf("exact EVs cover standard blackjack only, not %s", rules.Variant)
+	}
+	if rules.NoHoleCard {
+		return nil, fmt.Errorf("exact EVs assume the dealer peeks for blackjack")
+	}
+	return &Calculator{
+		rules:      rules,
+		advisor:    data.NewAdvisor(rules),
+		dealerMemo: make(map[dealerKey]DealerProbs),
+		evMemo:     make(map[evKey]float64),
+		bestMemo:   make(map[evKey]float64),
+	}, nil
+}
+
+func (c *Calculator) Rules() data.Rules {
+	return c.rules
+}
+
+// hand is a blackjack hand reduced to what play depends on. Cards are
+// stored by shoe index, with aces as 1.
+type hand struct {
+	hard  int
+	aces  int
+	cards int
+	first int
+	pair  bool
+	split bool
+}
+
+func (h *hand) add(v int) {
+	if h.cards == 0 {
+		h.first = v
+	}
+	h.pair = h.cards == 1 && v == h.first
+	h.cards++
+	h.hard += v
+	if v == 1 {
+		h.aces++
+	}
+}
+
+func (h hand) soft() bool {
+	return h.aces > 0 && h.hard+10 <= 21
+}
+
+func (h hand) value() int {
+	if h.soft() {
+		return h.hard + 10
+	}
+	return h.hard
+}
+
+func (h hand) situation() data.Situation {
+	s := data.Situation{
+		Total: h.value(),
+		Soft:  h.soft(),
+		Cards: h.cards,
+		Split: h.split,
+	}
+	if h.pair {
+		s.Pair = strategyValue(h.first)
+	}
+	return s
+}
+
+// strategyValue converts a shoe index to the scale the advisor uses, where
+// an ace is 11.
+func strategyValue(v int) int {
+	if v == 1 {
+		return 11
+	}
+	return v
+}
+
+type evKey struct {
+	shoe   Shoe
+	up     int
+	hand   hand
+	budget int
+}
+
+// Edge returns the player's expectation per initial bet for basic strategy
+// off the top of a full shoe. It is negative when the house has the edge.
+//
+// Every initial deal is enumerated with card removal. The dealer always
+// peeks, so once the hand is played the hole card is known not to complete
+// a blackjack; the player's own draws are not conditioned on this, which is
+// the usual simplification and is worth well under 0.01%.
+func (c *Calculator) Edge() float64 {
+	shoe := NewShoe(c.rules.Decks)
+	edge := 0.0
+	for p1 := 1; p1 <= 10; p1++ {
+		afterP1 := shoe.Without(p1)
+		for up := 1; up <= 10; up++ {
+			afterUp := afterP1.Without(up)
+			for p2 := 1; p2 <= 10; p2++ {
+				weight := shoe.Prob(p1) * afterP1.Prob(up) * afterUp.Prob(p2)
+				if weight == 0 {
+					continue
+				}
+				edge += weight * c.dealtEV(afterUp.Without(p2), up, p1, p2)
+			}
+		}
+	}
+	return edge
+}
+
+// dealtEV is the expectation of a two-card player hand once the dealer's
+// upcard is showing, before the dealer checks for blackjack.
+func (c *Calculator) dealtEV(shoe Shoe, up, p1, p2 int) float64 {
+	var h hand
+	h.add(p1)
+	h.add(p2)
+	dealerBlackjack := blackjackChance(shoe, up)
+	if h.value() == 21 {
+		return (1 - dealerBlackjack) * 1.5
+	}
+	return -dealerBlackjack + (1-dealerBlackjack)*c.handEV(shoe, up, h, c.rules.MaxSplitHands)
+}
+
+// handEV plays h by basic strategy against up. budget is the number of hands
+// h may still grow into by splitting.
+func (c *Calculator) handEV(shoe Shoe, up int, h hand, budget int) float64 {
+	key := evKey{shoe: shoe, up: up, hand: h, budget: budget}
+	if ev, ok := c.evMemo[key]; ok {
+		return ev
+	}
+	canSplit := h.pair && budget > 1
+	action, _ := c.advisor.RecommendSituation(h.situation(), strategyValue(up), canSplit, 0)
+	var ev float64
+	switch action {
+	case data.ActionHit:
+		ev = c.drawEV(shoe, up, h, func(next Shoe, nh hand) float64 {
+			return c.handEV(next, up, nh, 1)
+		})
+	case data.ActionDouble:
+		ev = 2 * c.drawEV(shoe, up, h, func(next Shoe, nh hand) float64 {
+			return c.standEV(next, up, nh.value())
+		})
+	case data.ActionSplit:
+		ev = c.splitEV(shoe, up, h.first, budget)
+	case data.ActionSurrender:
+		ev = -0.5
+	default:
+		ev = c.standEV(shoe, up, h.value())
+	}
+	c.evMemo[key] = ev
+	return ev
+}
+
+// drawEV averages then over every card h could draw, scoring a bust as a
+// loss of one unit and a Charlie as a win.
+func (c *Calculator) drawEV(shoe Shoe, up int, h hand, then func(Shoe, hand) float64) float64 {
+	total := shoe.Total()
+	if total == 0 {
+		return c.standEV(shoe, up, h.value())
+	}
+	ev := 0.0
+	for v := 1; v <= 10; v++ {
+		if shoe[v] == 0 {
+			continue
+		}
+		next := h
+		next.add(v)
+		p := float64(shoe[v]) / float64(total)
+		if next.value() > 21 {
+			ev -= p
+			continue
+		}
+		if c.charlie(next) {
+			ev += p
+			continue
+		}
+		ev += p * then(shoe.Without(v), next)
+	}
+	return ev
+}
+
+// charlie reports whether h holds enough cards to win outright.
+func (c *Calculator) charlie(h hand) bool {
+	n := c.rules.CharlieCards()
+	return n > 0 && h.cards >= n
+}
+
+// splitEV splits a pair of card into two hands that share budget between
+// them. Each hand is played against the same shoe as if the other did not
+// exist, which ignores the small effect of one split hand's cards on the
+// other.
+func (c *Calculator) splitEV(shoe Shoe, up, card, budget int) float64 {
+	ev := 0.0
+	for _, share := range [2]int{(budget + 1) / 2, budget / 2} {
+		start := hand{split: true}
+		start.add(card)
+		ev += c.drawEV(shoe, up, start, func(next Shoe, nh hand) float64 {
+			if c.oneCard(card) {
+				return c.standEV(next, up, nh.value())
+			}
+			return c.handEV(next, up, nh, share)
+		})
+	}
+	return ev
+}
+
+// oneCard reports whether each hand split from a pair of card stands on its
+// first card.
+func (c *Calculator) oneCard(card int) bool {
+	return card == 1 && !c.rules.HitSplitAces
+}
+
+// standEV is the expectation of standing on total once the dealer is known
+// not to hold blackjack.
+func (c *Calculator) standEV(shoe Shoe, up, total int) float64 {
+	probs := c.dealer(shoe, up, true)
+	ev := probs[DealerBust]
+	for result := Dealer17; result <= Dealer21; result++ {
+		dealerTotal := 17 + result - Dealer17
+		switch {
+		case total > dealerTotal:
+			ev += probs[result]
+		case total < dealerTotal:
+			ev -= probs[result]
+		}
+	}
+	return ev
+}
+
+// RuleEffect is the change in player expectation from one rule, stated as
+// Name, e.g. "S17 vs H17".
+type RuleEffect struct {
+	Name   string
+	Effect float64
+}
+
+func (e RuleEffect) String() string {
+	return fmt.Sprintf("%s: %+.2f%%", e.Name, e.Effect*100)
+}
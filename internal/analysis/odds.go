@@ -0,0 +1,48 @@
+package analysis
+
+import "blackjack/internal/data"
+
+// StandOdds is the chance of each result for a hand that stands.
+type StandOdds struct {
+	Win  float64
+	Push float64
+	Lose float64
+}
+
+// StandOdds returns the chances of winning, pushing and losing by standing
+// on total against upcard once the dealer has peeked, drawing from shoe.
+func (c *Calculator) StandOdds(shoe Shoe, upcard data.Card, total int) StandOdds {
+	probs := c.DealerOutcomes(shoe, upcard, true)
+	odds := StandOdds{Win: probs[DealerBust]}
+	for result := Dealer17; result <= Dealer21; result++ {
+		switch dealerTotal := 17 + result - Dealer17; {
+		case total > dealerTotal:
+			odds.Win += probs[result]
+		case total < dealerTotal:
+			odds.Lose += probs[result]
+		default:
+			odds.Push += probs[result]
+		}
+	}
+	return odds
+}
+
+// BustChance is the chance that the next card from shoe busts hand.
+func BustChance(hand *data.Hand, shoe Shoe) float64 {
+	total := shoe.Total()
+	if total == 0 {
+		return 0
+	}
+	// Soft hands count their ace as one once a card would bust them.
+	hard := 0
+	for _, card := range hand.Cards() {
+		hard += CardValue(card)
+	}
+	busting := 0
+	for v := 1; v <= 10; v++ {
+		if hard+v > 21 {
+			busting += shoe[v]
+		}
+	}
+	return float64(busting) / float64(total)
+}
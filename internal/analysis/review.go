@@ -0,0 +1,206 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+
+	"blackjack/internal/data"
+)
+
+// Alternative is one play that was open at a decision.
+type Alternative struct {
+	Action data.Action
+	// Result is what the play would have won or lost with the cards that
+	// actually came next, with basic strategy for any later decisions. It
+	// is NaN when the shoe ran out during the replay.
+	Result float64
+	// EV is the exact expectation over the cards that were unseen at the
+	// time, with perfect play for any later decisions.
+	EV float64
+}
+
+// ReviewStep pairs a decision with every play that was open at the time.
+// Results and EVs are in units of the hand's bet before the decision.
+type ReviewStep struct {
+	Decision     data.Decision
+	Alternatives []Alternative
+}
+
+// Chosen returns the alternative the player actually took.
+func (s ReviewStep) Chosen() Alternative {
+	for _, alt := range s.Alternatives {
+		if alt.Action == s.Decision.Action {
+			return alt
+		}
+	}
+	return Alternative{Action: s.Decision.Action, Result: math.NaN(), EV: math.NaN()}
+}
+
+// Review replays each of player's decisions in the settled round with every
+// alternative play.
+func (c *Calculator) Review(game *data.Game, player *data.Player) ([]ReviewStep, error) {
+	if game.State() != data.StateSettled {
+		return nil, fmt.Errorf("round review needs a settled round")
+	}
+	dealerCards := game.Dealer().ActiveHand().Cards()
+	if len(dealerCards) < 2 {
+		return nil, fmt.Errorf("dealer hand is incomplete")
+	}
+	up := CardValue(dealerCards[0])
+	hole := CardValue(dealerCards[1])
+	stream := append(append([]data.Card(nil), game.RoundCards()...), game.Deck().Remaining()...)
+
+	var steps []ReviewStep
+	for _, decision := range game.Decisions() {
+		if decision.Player != player {
+			continue
+		}
+		next := stream[decision.Drawn:]
+		// The hole card was dealt before any decision but was still unseen.
+		shoe := ShoeFromCards(next)
+		shoe[hole]++
+
+		state := decision.Hands[decision.Active]
+		unit := float64(state.Bet)
+		h := newHand(state.Cards, state.Split)
+		step := ReviewStep{Decision: decision}
+		for _, ev := range c.actionEVs(h, up, shoe, len(decision.Hands)) {
+			r := replay{calc: c, up: up, hole: hole, stream: next}
+			step.Alternatives = append(step.Alternatives, Alternative{
+				Action: ev.Action,
+				Result: r.run(decision, ev.Action) / unit,
+				EV:     ev.EV,
+			})
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func newHand(cards []data.Card, split bool) hand {
+	var h hand
+	for _, card := range cards {
+		h.add(CardValue(card))
+	}
+	h.split = split
+	return h
+}
+
+type replayHand struct {
+	hand
+	bet         float64
+	done        bool
+	surrendered bool
+	// reviewed marks the hand under review and any hands split from it.
+	reviewed bool
+}
+
+// replay plays out the rest of a round from a decision, drawing cards in
+// the order they actually left the shoe.
+type replay struct {
+	calc   *Calculator
+	up     int
+	hole   int
+	stream []data.Card
+	pos    int
+	short  bool
+	hands  []replayHand
+}
+
+func (r *replay) draw() int {
+	if r.pos == len(r.stream) {
+		r.short = true
+		return 10
+	}
+	card := r.stream[r.pos]
+	r.pos++
+	return CardValue(card)
+}
+
+// run takes action at decision, finishes the round with basic strategy and
+// returns the net result of the reviewed hands in dollars.
+func (r *replay) run(decision data.Decision, action data.Action) float64 {
+	for i, state := range decision.Hands {
+		r.hands = append(r.hands, replayHand{
+			hand:     newHand(state.Cards, state.Split),
+			bet:      float64(state.Bet),
+			done:     state.Standing,
+			reviewed: i == decision.Active,
+		})
+	}
+	r.apply(decision.Active, action)
+	maxHands := r.calc.rules.MaxSplitHands
+	for i := decision.Active; i < len(r.hands); i++ {
+		for !r.hands[i].done {
+			h := r.hands[i].hand
+			canSplit := h.pair && len(r.hands) < maxHands
+			next, _ := r.calc.advisor.RecommendSituation(h.situation(), strategyValue(r.up), canSplit, 0)
+			r.apply(i, next)
+		}
+	}
+
+	var dealer hand
+	dealer.add(r.up)
+	dealer.add(r.hole)
+	for data.DealerShouldHit(dealer.value(), dealer.soft(), r.calc.rules.DealerHitsSoft17) {
+		dealer.add(r.draw())
+	}
+	if r.short {
+		return math.NaN()
+	}
+
+	net := 0.0
+	for _, h := range r.hands {
+		if h.reviewed {
+			net += h.settle(dealer.value())
+		}
+	}
+	return net
+}
+
+func (r *replay) apply(i int, action data.Action) {
+	h := &r.hands[i]
+	switch action {
+	case data.ActionHit:
+		h.add(r.draw())
+		h.done = h.value() > 21
+	case data.ActionDouble:
+		h.bet *= 2
+		h.add(r.draw())
+		h.done = true
+	case data.ActionSplit:
+		card := h.first
+		split := replayHand{hand: hand{split: true}, bet: h.bet, reviewed: h.reviewed}
+		split.add(card)
+		first, second := split, split
+		first.add(r.draw())
+		second.add(r.draw())
+		first.done = r.calc.oneCard(card)
+		second.done = first.done
+		r.hands[i] = first
+		r.hands = append(r.hands[:i+1], append([]replayHand{second}, r.hands[i+1:]...)...)
+	case data.ActionSurrender:
+		h.surrendered = true
+		h.done = true
+	default:
+		h.done = true
+	}
+}
+
+func (h replayHand) settle(dealer int) float64 {
+	value := h.value()
+	switch {
+	case value > 21:
+		return -h.bet
+	case h.surrendered:
+		return -h.bet / 2
+	case h.cards == 2 && !h.split && value == 21:
+		return 1.5 * h.bet
+	case dealer > 21 || value > dealer:
+		return h.bet
+	case value < dealer:
+		return -h.bet
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,65 @@
+package analysis
+
+import "blackjack/internal/data"
+
+// Shoe counts the cards left by blackjack value, indexed 1 (ace) to 10.
+// Index 0 is unused.
+type Shoe [11]int
+
+func NewShoe(decks int) Shoe {
+	var s Shoe
+	for v := 1; v <= 9; v++ {
+		s[v] = 4 * decks
+	}
+	s[10] = 16 * decks
+	return s
+}
+
+// ShoeFromCards builds a composition from the cards left in a deck.
+func ShoeFromCards(cards []data.Card) Shoe {
+	var s Shoe
+	for _, card := range cards {
+		s[CardValue(card)]++
+	}
+	return s
+}
+
+// ShoeFromRanks builds a composition from counts indexed by data.Rank, as
+// returned by data.Deck.RankCounts.
+func ShoeFromRanks(counts [14]int) Shoe {
+	var s Shoe
+	for rank := data.Ace; rank <= data.King; rank++ {
+		s[min(int(rank), 10)] += counts[rank]
+	}
+	return s
+}
+
+// CardValue maps a card to the shoe index, with aces as 1.
+func CardValue(card data.Card) int {
+	return min(int(card.Rank), 10)
+}
+
+func (s Shoe) Total() int {
+	total := 0
+	for v := 1; v <= 10; v++ {
+		total += s[v]
+	}
+	return total
+}
+
+// Prob is the chance the next card has value v.
+func (s Shoe) Prob(v int) float64 {
+	total := s.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(s[v]) / float64(total)
+}
+
+// Without returns the shoe with one card of each given value removed.
+func (s Shoe) Without(values ...int) Shoe {
+	for _, v := range values {
+		s[v]--
+	}
+	return s
+}
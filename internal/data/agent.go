@@ -0,0 +1,181 @@
+package data
+
+import "fmt"
+
+// Agent decides bets and plays for a seat the game runs itself.
+type Agent interface {
+	// Bet returns the wager for the next round. A bet the table or the
+	// bankroll cannot take is clamped into range.
+	Bet(view TableView) int
+	// Play returns the action for the active hand in view.
+	Play(view TableView) Action
+}
+
+// SeatView is what the table can see of another seat.
+type SeatView struct {
+	Name  string
+	Hands []HandState
+}
+
+// TableView is a read-only copy of what a seat can see when it decides.
+type TableView struct {
+	Rules    Rules
+	Name     string
+	Bankroll int
+	Hands    []HandState
+	Active   int
+	// Upcard is the dealer's face-up card; it has a zero Rank before the
+	// deal and at tables that deal it face down.
+	Upcard Card
+	// Hole is the dealer's second card once it is face up.
+	Hole         Card
+	Others       []SeatView
+	RunningCount int
+	TrueCount    float64
+	CardsLeft    int
+}
+
+// ActiveHand returns the hand the seat is deciding on.
+func (v TableView) ActiveHand() HandState {
+	if v.Active >= len(v.Hands) {
+		return HandState{}
+	}
+	return v.Hands[v.Active]
+}
+
+// CanSplit reports whether the table limit allows another split.
+func (v TableView) CanSplit() bool {
+	return len(v.Hands) < v.Rules.MaxHands()
+}
+
+// Situation describes a hand snapshot for strategy lookups.
+func (s HandState) Situation() Situation {
+	hand := &Hand{cards: s.Cards, split: s.Split, doubled: s.Doubled}
+	return hand.Situation()
+}
+
+// View builds the table as seen from player's seat.
+func (g *Game) View(player *Player) TableView {
+	view := TableView{
+		Rules:        g.rules,
+		Name:         player.Name(),
+		Bankroll:     player.Bankroll(),
+		Hands:        handStates(player),
+		Active:       player.ActiveHandIndex(),
+		RunningCount: g.counter.Running(),
+		TrueCount:    g.TrueCount(),
+		CardsLeft:    g.deck.CardsLeft(),
+	}
+	if cards := g.dealer.ActiveHand().Cards(); len(cards) > 0 && !g.dealer.UpcardHidden() {
+		view.Upcard = cards[0]
+		if len(cards) > 1 && !g.dealer.HoleCardHidden() {
+			view.Hole = cards[1]
+		}
+	}
+	for _, other := range g.players {
+		if other != player {
+			view.Others = append(view.Others, SeatView{Name: other.Name(), Hands: handStates(other)})
+		}
+	}
+	return view
+}
+
+func handStates(player *Player) []HandState {
+	hands := player.Hands()
+	states := make([]HandState, len(hands))
+	for i, hand := range hands {
+		states[i] = HandState{
+			Cards:    append([]Card(nil), hand.Cards()...),
+			Bet:      hand.Bet(),
+			Split:    hand.IsSplit(),
+			Doubled:  hand.IsDoubleDown(),
+			Standing: hand.IsStanding() || hand.IsBusted(),
+		}
+	}
+	return states
+}
+
+// dropBrokeAgents removes agent seats that can no longer cover the table
+// minimum, as a broke player would leave the table.
+func (g *Game) dropBrokeAgents() {
+	seated := g.players[:0]
+	for _, player := range g.players {
+		if player.Agent() != nil && player.Bankroll() < max(g.rules.MinBet, 1)*g.rules.Boxes() {
+			continue
+		}
+		seated = append(seated, player)
+	}
+	g.players = seated
+}
+
+// agentBet asks an agent seat for its bet, clamped to the table limits and
+// its bankroll.
+func (g *Game) agentBet(player *Player) int {
+	bet := player.Agent().Bet(g.View(player))
+	bet = max(bet, g.rules.MinBet, 1)
+	if g.rules.MaxBet > 0 {
+		bet = min(bet, g.rules.MaxBet)
+	}
+	return min(bet, player.Bankroll()/g.rules.Boxes())
+}
+
+// PlayAgents plays every hand of every agent seat to completion, in seat
+// order.
+func (g *Game) PlayAgents() error {
+	for _, player := range g.players {
+		if player.Agent() == nil {
+			continue
+		}
+		if err := g.playAgent(player); err != nil {
+			return fmt.Errorf("player %s: %w", player.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (g *Game) playAgent(player *Player) error {
+	if g.state != StatePlayerAction {
+		return ErrInvalidState
+	}
+	for {
+		hand := player.ActiveHand()
+		if hand == nil || hand.IsStanding() || hand.IsBusted() {
+			return nil
+		}
+		if hand.IsBlackjack() {
+			if err := g.Stand(player); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := g.apply(player, player.Agent().Play(g.View(player))); err != nil {
+			// An action the table or bankroll refuses is played as a hit,
+			// the way a dealer treats "double" on a short stack. A doubled
+			// hand left open for a rescue can only stand instead.
+			fallback := ActionHit
+			if hand.IsDoubleDown() && !g.rules.Buys() {
+				fallback = ActionStand
+			}
+			if err := g.apply(player, fallback); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (g *Game) apply(player *Player, action Action) error {
+	var err error
+	switch action {
+	case ActionHit:
+		_, err = g.Hit(player)
+	case ActionDouble:
+		_, err = g.DoubleDown(player)
+	case ActionSplit:
+		_, _, err = g.Split(player)
+	case ActionSurrender:
+		err = g.Surrender(player)
+	default:
+		err = g.Stand(player)
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package data
+
+import "testing"
+
+func TestGameWithBots(t *testing.T) {
+	rules := DefaultRules()
+	game, err := NewGameWithRules(rules, []PlayerConfig{
+		{Name: "You", Bankroll: 1000},
+		{Name: "Basic", Bankroll: 1000, Agent: NewBasicBot(rules)},
+		{Name: "Never", Bankroll: 1000, Agent: NeverBustBot{}},
+		{Name: "Dealer", Bankroll: 1000, Agent: DealerBot{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.Seed(3)
+	human := game.Players()[0]
+
+	for round := 0; round < 50; round++ {
+		if err := game.StartRound(map[string]int{"You": 10}); err != nil {
+			t.Fatalf("round %d: unexpected start round error: %v", round, err)
+		}
+		for _, player := range game.Players()[1:] {
+			if bet := player.Hands()[0].Bet(); bet != rules.MinBet {
+				t.Fatalf("round %d: %s bet %d, want the table minimum", round, player.Name(), bet)
+			}
+		}
+		if err := game.DealInitialCards(); err != nil {
+			t.Fatalf("round %d: unexpected deal error: %v", round, err)
+		}
+		if !human.ActiveHand().IsStanding() {
+			if err := game.Stand(human); err != nil {
+				t.Fatalf("round %d: unexpected stand error: %v", round, err)
+			}
+		}
+		if err := game.PlayAgents(); err != nil {
+			t.Fatalf("round %d: unexpected bot error: %v", round, err)
+		}
+		if !game.ReadyForDealer() {
+			t.Fatalf("round %d: bots left hands unplayed", round)
+		}
+		for _, hand := range game.Players()[2].Hands() {
+			if hand.IsBusted() {
+				t.Fatalf("round %d: never-bust bot busted with %v", round, hand.Cards())
+			}
+		}
+		if err := game.DealerPlay(); err != nil {
+			t.Fatalf("round %d: unexpected dealer error: %v", round, err)
+		}
+		if _, err := game.SettleRound(); err != nil {
+			t.Fatalf("round %d: unexpected settle error: %v", round, err)
+		}
+		game.PrepareNextRound()
+	}
+}
+
+func TestTableView(t *testing.T) {
+	rules := DefaultRules()
+	game, err := NewGameWithRules(rules, []PlayerConfig{
+		{Name: "You", Bankroll: 100},
+		{Name: "Bot", Bankroll: 100, Agent: DealerBot{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.deck.cards = []Card{
+		{Suit: Spades, Rank: Ten},   // You
+		{Suit: Hearts, Rank: Nine},  // Bot
+		{Suit: Clubs, Rank: Six},    // dealer upcard
+		{Suit: Spades, Rank: Six},   // You
+		{Suit: Hearts, Rank: Seven}, // Bot
+		{Suit: Clubs, Rank: Ace},    // hole card
+	}
+	if err := game.StartRound(map[string]int{"You": 10}); err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	if err := game.DealInitialCards(); err != nil {
+		t.Fatalf("unexpected deal error: %v", err)
+	}
+
+	bot := game.Players()[1]
+	view := game.View(bot)
+	if view.Upcard.Rank != Six {
+		t.Fatalf("expected upcard 6, got %v", view.Upcard)
+	}
+	if len(view.Others) != 1 || view.Others[0].Name != "You" || len(view.Others[0].Hands[0].Cards) != 2 {
+		t.Fatalf("expected to see the other seat's two cards, got %+v", view.Others)
+	}
+	if got := view.ActiveHand().Situation().Total; got != 16 {
+		t.Fatalf("expected the bot to hold 16, got %d", got)
+	}
+	if got := (DealerBot{}).Play(view); got != ActionHit {
+		t.Fatalf("dealer bot should hit 16, got %v", got)
+	}
+	if got := (NeverBustBot{}).Play(view); got != ActionStand {
+		t.Fatalf("never-bust bot should stand on 16, got %v", got)
+	}
+	// Changing the view must not touch the game.
+	view.Hands[0].Cards[0] = Card{Suit: Spades, Rank: Ace}
+	if bot.ActiveHand().Cards()[0].Rank != Nine {
+		t.Fatal("table view shares cards with the game")
+	}
+}
+
+func TestBrokeBotLeavesTable(t *testing.T) {
+	rules := DefaultRules()
+	game, err := NewGameWithRules(rules, []PlayerConfig{
+		{Name: "You", Bankroll: 100},
+		{Name: "Bot", Bankroll: rules.MinBet - 1, Agent: DealerBot{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	if err := game.StartRound(map[string]int{"You": 10}); err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	if got := len(game.Players()); got != 1 {
+		t.Fatalf("expected the broke bot to leave, %d players remain", got)
+	}
+}
@@ -0,0 +1,163 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RampStep bets Units table units once the floored true count reaches
+// TrueCount.
+type RampStep struct {
+	TrueCount int
+	Units     int
+}
+
+// BetRamp is a betting spread keyed by true count.
+type BetRamp struct {
+	Steps []RampStep
+}
+
+// DefaultRamp is a conventional 1-8 spread for a six deck shoe.
+func DefaultRamp() BetRamp {
+	return BetRamp{Steps: []RampStep{
+		{TrueCount: 1, Units: 1},
+		{TrueCount: 2, Units: 2},
+		{TrueCount: 3, Units: 4},
+		{TrueCount: 4, Units: 6},
+		{TrueCount: 5, Units: 8},
+	}}
+}
+
+// ParseRamp reads a ramp written as comma separated "tc:units" pairs, for
+// example "1:1,2:2,3:4,4:8".
+func ParseRamp(s string) (BetRamp, error) {
+	var ramp BetRamp
+	for _, part := range strings.Split(s, ",") {
+		tcText, unitsText, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return BetRamp{}, fmt.Errorf("ramp step %q must look like tc:units", part)
+		}
+		tc, err := strconv.Atoi(strings.TrimSpace(tcText))
+		if err != nil {
+			return BetRamp{}, fmt.Errorf("ramp step %q has an invalid true count: %w", part, err)
+		}
+		units, err := strconv.Atoi(strings.TrimSpace(unitsText))
+		if err != nil || units <= 0 {
+			return BetRamp{}, fmt.Errorf("ramp step %q must bet a positive number of units", part)
+		}
+		ramp.Steps = append(ramp.Steps, RampStep{TrueCount: tc, Units: units})
+	}
+	sort.Slice(ramp.Steps, func(i, j int) bool {
+		return ramp.Steps[i].TrueCount < ramp.Steps[j].TrueCount
+	})
+	return ramp, nil
+}
+
+// Units returns the number of units to bet at trueCount. Counts below the
+// first step bet that step's units.
+func (r BetRamp) Units(trueCount float64) int {
+	if len(r.Steps) == 0 {
+		return 1
+	}
+	tc := int(math.Floor(trueCount))
+	units := r.Steps[0].Units
+	for _, step := range r.Steps {
+		if tc < step.TrueCount {
+			break
+		}
+		units = step.Units
+	}
+	return units
+}
+
+func (r BetRamp) String() string {
+	parts := make([]string, len(r.Steps))
+	for i, step := range r.Steps {
+		parts[i] = fmt.Sprintf("%d:%d", step.TrueCount, step.Units)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Kelly sizes bets as a fraction of the Kelly criterion. Advantage is
+// estimated as the rules' off-the-top edge plus EdgePerTC for every point
+// of true count, so rules without a published edge cannot be sized.
+type Kelly struct {
+	Fraction  float64
+	EdgePerTC float64
+	Variance  float64
+}
+
+func DefaultKelly() Kelly {
+	return Kelly{Fraction: 0.5, EdgePerTC: 0.005, Variance: 1.3}
+}
+
+func (k Kelly) Advantage(rules Rules, trueCount float64) (float64, bool) {
+	edge, ok := rules.EstimatedHouseEdge()
+	return k.EdgePerTC*trueCount - edge, ok
+}
+
+// Bet returns the fractional Kelly wager, or zero when the player has no
+// advantage or it cannot be estimated.
+func (k Kelly) Bet(rules Rules, trueCount float64, bankroll int) float64 {
+	advantage, ok := k.Advantage(rules, trueCount)
+	if !ok || advantage <= 0 || k.Variance <= 0 {
+		return 0
+	}
+	return k.Fraction * advantage / k.Variance * float64(bankroll)
+}
+
+// BetAdvisor suggests the next wager from the live count. When Ramp is nil
+// the suggestion comes from Kelly instead.
+type BetAdvisor struct {
+	Rules Rules
+	Unit  int
+	Ramp  *BetRamp
+	Kelly Kelly
+}
+
+func NewBetAdvisor(rules Rules) *BetAdvisor {
+	ramp := DefaultRamp()
+	return &BetAdvisor{
+		Rules: rules,
+		Unit:  max(rules.MinBet, 1),
+		Ramp:  &ramp,
+		Kelly: DefaultKelly(),
+	}
+}
+
+// Suggest returns a bet rounded to whole units and clamped to the table
+// limits and the player's bankroll. It returns zero only when the bankroll
+// cannot cover the table minimum.
+func (a *BetAdvisor) Suggest(trueCount float64, bankroll int) int {
+	unit := max(a.Unit, 1)
+	var bet int
+	if a.Ramp != nil {
+		bet = a.Ramp.Units(trueCount) * unit
+	} else {
+		bet = int(a.Kelly.Bet(a.Rules, trueCount, bankroll)/float64(unit)) * unit
+	}
+	return a.Rules.CapBet(bet, bankroll)
+}
+
+// Describe explains how the current suggestion was reached.
+func (a *BetAdvisor) Describe(trueCount float64) string {
+	if a.Ramp != nil {
+		return fmt.Sprintf("%d units at TC %+.1f", a.Ramp.Units(trueCount), trueCount)
+	}
+	advantage, ok := a.Kelly.Advantage(a.Rules, trueCount)
+	if !ok {
+		return fmt.Sprintf("%.0f%% Kelly, no published %s edge", a.Kelly.Fraction*100, a.Rules.Variant)
+	}
+	return fmt.Sprintf("%.0f%% Kelly, edge %+.2f%% at TC %+.1f",
+		a.Kelly.Fraction*100, advantage*100, trueCount)
+}
+
+func (a *BetAdvisor) String() string {
+	if a.Ramp != nil {
+		return fmt.Sprintf("ramp %s with a $%d unit", a.Ramp, a.Unit)
+	}
+	return fmt.Sprintf("%.0f%% Kelly with a $%d unit", a.Kelly.Fraction*100, a.Unit)
+}
@@ -0,0 +1,86 @@
+package data
+
+import "testing"
+
+func TestParseRamp(t *testing.T) {
+	ramp, err := ParseRamp("3:4, 1:1,2:2")
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if ramp.String() != "1:1,2:2,3:4" {
+		t.Fatalf("expected steps to be sorted, got %s", ramp.String())
+	}
+	tests := []struct {
+		trueCount float64
+		expected  int
+	}{
+		{-3, 1},
+		{1.9, 1},
+		{2, 2},
+		{2.5, 2},
+		{7, 4},
+	}
+	for _, test := range tests {
+		if got := ramp.Units(test.trueCount); got != test.expected {
+			t.Errorf("Units(%v) = %d, want %d", test.trueCount, got, test.expected)
+		}
+	}
+
+	for _, bad := range []string{"1", "x:1", "1:0", "1:-2"} {
+		if _, err := ParseRamp(bad); err == nil {
+			t.Errorf("expected error parsing %q", bad)
+		}
+	}
+}
+
+func TestBetAdvisorRamp(t *testing.T) {
+	rules := DefaultRules()
+	rules.MinBet = 10
+	rules.MaxBet = 50
+	advisor := NewBetAdvisor(rules)
+
+	if got := advisor.Suggest(-2, 1000); got != 10 {
+		t.Errorf("expected table minimum at negative count, got %d", got)
+	}
+	if got := advisor.Suggest(3, 1000); got != 40 {
+		t.Errorf("expected 4 units at TC +3, got %d", got)
+	}
+	if got := advisor.Suggest(5, 1000); got != 50 {
+		t.Errorf("expected bet capped at table maximum, got %d", got)
+	}
+	if got := advisor.Suggest(5, 25); got != 25 {
+		t.Errorf("expected bet capped at bankroll, got %d", got)
+	}
+	if got := advisor.Suggest(5, 5); got != 0 {
+		t.Errorf("expected no bet when bankroll is below minimum, got %d", got)
+	}
+}
+
+func TestBetAdvisorKelly(t *testing.T) {
+	rules := DefaultRules()
+	rules.MaxBet = 0
+	advisor := NewBetAdvisor(rules)
+	advisor.Ramp = nil
+
+	if got := advisor.Suggest(0, 10000); got != rules.MinBet {
+		t.Errorf("expected table minimum without an edge, got %d", got)
+	}
+	low := advisor.Suggest(3, 10000)
+	high := advisor.Suggest(6, 10000)
+	if low <= rules.MinBet || high <= low {
+		t.Errorf("expected Kelly bets to grow with the count, got %d then %d", low, high)
+	}
+	if low%advisor.Unit != 0 {
+		t.Errorf("expected bet rounded to whole units, got %d", low)
+	}
+}
+
+func TestBetAdvisorKellyWithoutPublishedEdge(t *testing.T) {
+	rules := pontoonRules()
+	rules.MaxBet = 0
+	advisor := NewBetAdvisor(rules)
+	advisor.Ramp = nil
+	if got := advisor.Suggest(6, 10000); got != rules.MinBet {
+		t.Errorf("expected table minimum with no edge to size from, got %d", got)
+	}
+}
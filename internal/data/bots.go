@@ -0,0 +1,118 @@
+package data
+
+import "fmt"
+
+// BasicBot flat bets the table minimum and plays basic strategy.
+type BasicBot struct {
+	Advisor *Advisor
+}
+
+func NewBasicBot(rules Rules) *BasicBot {
+	return &BasicBot{Advisor: NewAdvisor(rules)}
+}
+
+func (b *BasicBot) String() string {
+	return "basic strategy bot"
+}
+
+func (b *BasicBot) Bet(view TableView) int {
+	return view.Rules.MinBet
+}
+
+func (b *BasicBot) Play(view TableView) Action {
+	action, _ := b.Advisor.recommendView(view, 0)
+	return action
+}
+
+// CounterBot spreads its bets with the count and plays index plays.
+type CounterBot struct {
+	Advisor *Advisor
+	Bets    *BetAdvisor
+	System  CountSystem
+}
+
+// NewCounterBot builds a counter that uses the default ramp and the
+// built-in index table for system.
+func NewCounterBot(rules Rules, system CountSystem) (*CounterBot, error) {
+	table, err := DefaultIndexTable(system)
+	if err != nil {
+		return nil, err
+	}
+	advisor := NewAdvisor(rules)
+	advisor.Deviations = table
+	return &CounterBot{Advisor: advisor, Bets: NewBetAdvisor(rules), System: system}, nil
+}
+
+func (b *CounterBot) String() string {
+	return b.System.Name + " counter bot"
+}
+
+func (b *CounterBot) Bet(view TableView) int {
+	return b.Bets.Suggest(view.TrueCount, view.Bankroll)
+}
+
+func (b *CounterBot) Play(view TableView) Action {
+	action, _ := b.Advisor.recommendView(view, view.TrueCount)
+	return action
+}
+
+// NeverBustBot never hits a hand that could bust, and stands on soft 18 or
+// better.
+type NeverBustBot struct{}
+
+func (NeverBustBot) String() string {
+	return "never-bust bot"
+}
+
+func (NeverBustBot) Bet(view TableView) int {
+	return view.Rules.MinBet
+}
+
+func (NeverBustBot) Play(view TableView) Action {
+	s := view.ActiveHand().Situation()
+	if s.Total <= 11 || s.Soft && s.Total <= 17 {
+		return ActionHit
+	}
+	return ActionStand
+}
+
+// DealerBot plays its hand by the dealer's drawing rule.
+type DealerBot struct{}
+
+func (DealerBot) String() string {
+	return "dealer-mimic bot"
+}
+
+func (DealerBot) Bet(view TableView) int {
+	return view.Rules.MinBet
+}
+
+func (DealerBot) Play(view TableView) Action {
+	s := view.ActiveHand().Situation()
+	if DealerShouldHit(s.Total, s.Soft, view.Rules.DealerHitsSoft17) {
+		return ActionHit
+	}
+	return ActionStand
+}
+
+// NewBot returns the bot with the given name: basic, counter, never-bust or
+// dealer. The counter bot keeps the given count.
+func NewBot(name string, rules Rules, system CountSystem) (Agent, error) {
+	switch name {
+	case "basic":
+		return NewBasicBot(rules), nil
+	case "counter":
+		return NewCounterBot(rules, system)
+	case "never-bust":
+		return NeverBustBot{}, nil
+	case "dealer":
+		return DealerBot{}, nil
+	default:
+		return nil, fmt.Errorf("unknown bot %q (want basic, counter, never-bust or dealer)", name)
+	}
+}
+
+// BotNames lists the bots NewBot knows, for help text.
+func BotNames() []string {
+	return []string{"basic", "counter", "never-bust", "dealer"}
+}
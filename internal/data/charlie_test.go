@@ -0,0 +1,105 @@
+package data
+
+import "testing"
+
+func charlieRules() Rules {
+	rules := DefaultRules()
+	rules.Charlie = 5
+	return rules
+}
+
+func TestCharlieRound(t *testing.T) {
+	game, player := newRiggedGame(t, charlieRules(), []Card{
+		{Suit: Spades, Rank: Two},    // player card 1
+		{Suit: Clubs, Rank: Ten},     // dealer upcard
+		{Suit: Hearts, Rank: Three},  // player card 2
+		{Suit: Diamonds, Rank: Nine}, // dealer hole card
+		{Suit: Spades, Rank: Four},
+		{Suit: Hearts, Rank: Two},
+		{Suit: Clubs, Rank: Three}, // fifth card makes the Charlie
+	})
+	hand := player.ActiveHand()
+	for i := 0; i < 3; i++ {
+		if hand.IsStanding() {
+			t.Fatalf("expected the hand open after %d cards", len(hand.Cards()))
+		}
+		if _, err := game.Hit(player); err != nil {
+			t.Fatalf("unexpected hit error: %v", err)
+		}
+	}
+	if !hand.IsStanding() {
+		t.Fatal("expected the fifth card to end the hand")
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected game to be ready for dealer")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer play error: %v", err)
+	}
+	results, err := game.SettleRound()
+	if err != nil {
+		t.Fatalf("unexpected settle round error: %v", err)
+	}
+	if results[0].Outcome != OutcomeCharlie || results[0].Bonus != NoBonus {
+		t.Fatalf("expected a plain Charlie, got %v with %v", results[0].Outcome, results[0].Bonus)
+	}
+	if player.Bankroll() != 110 {
+		t.Errorf("expected 14 to beat 19 at even money, got bankroll %d", player.Bankroll())
+	}
+}
+
+func TestCharlieLosesToDealerBlackjack(t *testing.T) {
+	rules := charlieRules()
+	rules.NoHoleCard = true
+	hand := newTestHand(Card{Spades, Two}, Card{Hearts, Three}, Card{Clubs, Four}, Card{Clubs, Two}, Card{Hearts, Two})
+	blackjack := newTestHand(Card{Clubs, Ace}, Card{Clubs, Queen})
+	if outcome, _ := rules.settle(hand, blackjack, OutcomeLose); outcome != OutcomeLose {
+		t.Errorf("expected a dealer blackjack to beat a Charlie, got %v", outcome)
+	}
+	twenty := newTestHand(Card{Clubs, King}, Card{Clubs, Queen})
+	if outcome, _ := rules.settle(hand, twenty, OutcomeLose); outcome != OutcomeCharlie {
+		t.Errorf("expected a Charlie to beat a dealer 20, got %v", outcome)
+	}
+	if outcome, _ := DefaultRules().settle(hand, twenty, OutcomeLose); outcome != OutcomeLose {
+		t.Errorf("expected no Charlie without the rule, got %v", outcome)
+	}
+}
+
+func TestCharlieValidate(t *testing.T) {
+	for _, charlie := range []int{-1, 3, 4} {
+		rules := DefaultRules()
+		rules.Charlie = charlie
+		if err := rules.Validate(); err == nil {
+			t.Errorf("expected a %d-card Charlie to be rejected", charlie)
+		}
+	}
+	rules := pontoonRules()
+	rules.Charlie = 6
+	if err := rules.Validate(); err == nil {
+		t.Error("expected a six-card Pontoon trick to be rejected")
+	}
+}
+
+func TestAdvisorCharlie(t *testing.T) {
+	advisor := NewAdvisor(charlieRules())
+	standard := NewAdvisor(DefaultRules())
+	six := Card{Suit: Clubs, Rank: Six}
+	tests := []struct {
+		name     string
+		hand     *Hand
+		expected Action
+	}{
+		{"four-card soft 18", newTestHand(Card{Spades, Ace}, Card{Hearts, Two}, Card{Clubs, Two}, Card{Clubs, Three}), ActionHit},
+		{"four-card 15", newTestHand(Card{Spades, Two}, Card{Hearts, Three}, Card{Clubs, Four}, Card{Clubs, Six}), ActionHit},
+		{"four-card 16", newTestHand(Card{Spades, Two}, Card{Hearts, Three}, Card{Clubs, Four}, Card{Clubs, Seven}), ActionStand},
+		{"three-card 15", newTestHand(Card{Spades, Two}, Card{Hearts, Four}, Card{Clubs, Nine}), ActionStand},
+	}
+	for _, test := range tests {
+		if got := advisor.Recommend(test.hand, six, false, 0); got != test.expected {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.expected)
+		}
+		if got := standard.Recommend(test.hand, six, false, 0); got != ActionStand {
+			t.Errorf("%s without a Charlie: got %v, want %v", test.name, got, ActionStand)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// CountSystem assigns a counting tag to every rank.
+type CountSystem struct {
+	Name     string
+	Tags     [14]int
+	Balanced bool
+}
+
+var (
+	HiLo = CountSystem{
+		Name:     "Hi-Lo",
+		Tags:     [14]int{0, -1, 1, 1, 1, 1, 1, 0, 0, 0, -1, -1, -1, -1},
+		Balanced: true,
+	}
+	KO = CountSystem{
+		Name:     "KO",
+		Tags:     [14]int{0, -1, 1, 1, 1, 1, 1, 1, 0, 0, -1, -1, -1, -1},
+		Balanced: false,
+	}
+	HiOptI = CountSystem{
+		Name:     "Hi-Opt I",
+		Tags:     [14]int{0, 0, 0, 1, 1, 1, 1, 0, 0, 0, -1, -1, -1, -1},
+		Balanced: true,
+	}
+	OmegaII = CountSystem{
+		Name:     "Omega II",
+		Tags:     [14]int{0, 0, 1, 1, 2, 2, 2, 1, 0, -1, -2, -2, -2, -2},
+		Balanced: true,
+	}
+)
+
+var countSystems = map[string]CountSystem{
+	"hilo":   HiLo,
+	"ko":     KO,
+	"hiopt1": HiOptI,
+	"omega2": OmegaII,
+}
+
+// LookupCountSystem finds a counting system by its short name, ignoring case.
+func LookupCountSystem(name string) (CountSystem, error) {
+	system, ok := countSystems[strings.ToLower(name)]
+	if !ok {
+		return CountSystem{}, fmt.Errorf("unknown count system %q (available: %s)", name, strings.Join(CountSystemNames(), ", "))
+	}
+	return system, nil
+}
+
+// CountSystemNames lists the short names accepted by LookupCountSystem.
+func CountSystemNames() []string {
+	names := make([]string, 0, len(countSystems))
+	for name := range countSystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s CountSystem) Tag(card Card) int {
+	return s.Tags[card.Rank]
+}
+
+func (s CountSystem) RunningCount(cards []Card) int {
+	count := 0
+	for _, card := range cards {
+		count += s.Tag(card)
+	}
+	return count
+}
+
+// TrueCount converts a running count into a per-deck count using the number
+// of cards still in the shoe.
+func TrueCount(running, cardsLeft int) float64 {
+	if cardsLeft <= 0 {
+		return 0
+	}
+	return float64(running) / (float64(cardsLeft) / 52)
+}
+
+// CheckTrueCount accepts an answer that is the exact true count rounded
+// either up or down, since players differ on which convention they drill.
+func CheckTrueCount(answer, running, cardsLeft int) bool {
+	exact := TrueCount(running, cardsLeft)
+	return int(math.Floor(exact)) == answer || int(math.Ceil(exact)) == answer
+}
+
+// DrillHoldback picks how many of a shuffled shoe's cards a countdown
+// drill leaves undealt: between one and a quarter of them. A balanced count
+// of the whole shoe is always zero, so the held back cards are what make
+// the answer worth checking. A nil rng uses the package source.
+func DrillHoldback(rng *rand.Rand, cards int) int {
+	most := max(cards/4, 1)
+	if rng != nil {
+		return rng.Intn(most) + 1
+	}
+	return rand.Intn(most) + 1
+}
+
+// Counter keeps a running count of every card the table has seen.
+type Counter struct {
+	system  CountSystem
+	running int
+	seen    int
+}
+
+func NewCounter(system CountSystem) *Counter {
+	return &Counter{system: system}
+}
+
+func (c *Counter) Observe(card Card) {
+	c.running += c.system.Tag(card)
+	c.seen++
+}
+
+func (c *Counter) System() CountSystem {
+	return c.system
+}
+
+func (c *Counter) Running() int {
+	return c.running
+}
+
+func (c *Counter) Seen() int {
+	return c.seen
+}
+
+func (c *Counter) TrueCount(cardsLeft int) float64 {
+	return TrueCount(c.running, cardsLeft)
+}
+
+func (c *Counter) Reset() {
+	c.running = 0
+	c.seen = 0
+}
@@ -0,0 +1,80 @@
+package data
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBalancedSystemsSumToZero(t *testing.T) {
+	for _, name := range CountSystemNames() {
+		system, err := LookupCountSystem(name)
+		if err != nil {
+			t.Fatalf("unexpected lookup error: %v", err)
+		}
+		deck := NewDeck(1)
+		got := system.RunningCount(deck.cards)
+		if system.Balanced && got != 0 {
+			t.Errorf("%s: expected full deck to count to 0, got %d", system.Name, got)
+		}
+		if !system.Balanced && got == 0 {
+			t.Errorf("%s: expected unbalanced system to end off zero", system.Name)
+		}
+	}
+}
+
+func TestLookupCountSystemUnknown(t *testing.T) {
+	if _, err := LookupCountSystem("nope"); err == nil {
+		t.Fatal("expected error for unknown count system")
+	}
+}
+
+func TestTrueCount(t *testing.T) {
+	tests := []struct {
+		running   int
+		cardsLeft int
+		expected  float64
+	}{
+		{6, 156, 2},
+		{-4, 104, -2},
+		{5, 26, 10},
+		{3, 0, 0},
+	}
+
+	for _, test := range tests {
+		if got := TrueCount(test.running, test.cardsLeft); got != test.expected {
+			t.Errorf("TrueCount(%d, %d) = %v, want %v", test.running, test.cardsLeft, got, test.expected)
+		}
+	}
+}
+
+func TestDrillHoldbackVariesTheCount(t *testing.T) {
+	counts := map[int]bool{}
+	for seed := int64(1); seed <= 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		deck := NewDeck(1)
+		deck.UseRand(rng)
+		deck.Shuffle()
+		held := DrillHoldback(rng, deck.CardsLeft())
+		if held < 1 || held > 13 {
+			t.Fatalf("seed %d: held back %d cards, want 1 to 13", seed, held)
+		}
+		var dealt []Card
+		for deck.CardsLeft() > held {
+			dealt = append(dealt, deck.Deal())
+		}
+		counts[HiLo.RunningCount(dealt)] = true
+	}
+	if len(counts) < 2 {
+		t.Fatalf("expected the drill answer to vary, got only %v", counts)
+	}
+}
+
+func TestCheckTrueCount(t *testing.T) {
+	// 7 running with three decks left is a true count of 2.33.
+	if !CheckTrueCount(2, 7, 156) || !CheckTrueCount(3, 7, 156) {
+		t.Fatal("expected both floor and ceiling answers to be accepted")
+	}
+	if CheckTrueCount(4, 7, 156) {
+		t.Fatal("expected answer two away to be rejected")
+	}
+}
@@ -6,16 +6,23 @@ import (
 
 type Deck struct {
 	cards []Card
+	rng   *rand.Rand
 }
 
 func NewDeck(numDecks int) *Deck {
+	return NewDeckOf(numDecks, Standard.Ranks())
+}
+
+// NewDeckOf builds a shoe of numDecks decks holding only ranks, such as
+// the Spanish decks without tens.
+func NewDeckOf(numDecks int, ranks []Rank) *Deck {
 	deck := &Deck{}
 	if numDecks < 0 {
 		return deck
 	}
 
 	for suit := Hearts; suit <= Spades; suit++ {
-		for rank := Ace; rank <= King; rank++ {
+		for _, rank := range ranks {
 			deck.cards = append(deck.cards, Card{Suit: suit, Rank: rank})
 		}
 	}
@@ -28,10 +35,21 @@ func NewDeck(numDecks int) *Deck {
 	return outDeck
 }
 
+// UseRand makes future shuffles draw from r, so a seeded source gives a
+// reproducible order.
+func (d *Deck) UseRand(r *rand.Rand) {
+	d.rng = r
+}
+
 func (d *Deck) Shuffle() {
-	rand.Shuffle(len(d.cards), func(i, j int) {
+	swap := func(i, j int) {
 		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
-	})
+	}
+	if d.rng != nil {
+		d.rng.Shuffle(len(d.cards), swap)
+		return
+	}
+	rand.Shuffle(len(d.cards), swap)
 }
 
 func (d *Deck) Deal() Card {
@@ -46,3 +64,18 @@ func (d *Deck) Deal() Card {
 func (d *Deck) CardsLeft() int {
 	return len(d.cards)
 }
+
+// RankCounts returns how many cards of each rank are left, indexed by Rank.
+func (d *Deck) RankCounts() [14]int {
+	var counts [14]int
+	for _, card := range d.cards {
+		counts[card.Rank]++
+	}
+	return counts
+}
+
+// Remaining returns a copy of the cards left, in the order they will be
+// dealt.
+func (d *Deck) Remaining() []Card {
+	return append([]Card(nil), d.cards...)
+}
@@ -0,0 +1,146 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// HandKind says how an index play identifies the player's hand.
+type HandKind string
+
+const (
+	KindHard HandKind = "hard"
+	KindSoft HandKind = "soft"
+	KindPair HandKind = "pair"
+)
+
+// IndexPlay is a count-based departure from basic strategy. The play applies
+// once the true count reaches Index, or drops below it when Below is set.
+// Total is the hand total, or the value of one card for pairs; Upcard uses
+// 11 for an ace.
+type IndexPlay struct {
+	Name   string   `json:"name"`
+	Kind   HandKind `json:"kind"`
+	Total  int      `json:"total,omitempty"`
+	Upcard int      `json:"upcard,omitempty"`
+	Index  float64  `json:"index"`
+	Below  bool     `json:"below,omitempty"`
+	Action Action   `json:"action"`
+}
+
+func (p IndexPlay) Triggered(trueCount float64) bool {
+	if p.Below {
+		return trueCount < p.Index
+	}
+	return trueCount >= p.Index
+}
+
+// IndexTable holds the index plays for one counting system.
+type IndexTable struct {
+	System string      `json:"system"`
+	Plays  []IndexPlay `json:"plays"`
+}
+
+var (
+	// Illustrious18 leaves out its insurance index, since the table offers
+	// no insurance.
+	Illustrious18 = []IndexPlay{
+		{Name: "16 v 10", Kind: KindHard, Total: 16, Upcard: 10, Index: 0, Action: ActionStand},
+		{Name: "15 v 10", Kind: KindHard, Total: 15, Upcard: 10, Index: 4, Action: ActionStand},
+		{Name: "10,10 v 5", Kind: KindPair, Total: 10, Upcard: 5, Index: 5, Action: ActionSplit},
+		{Name: "10,10 v 6", Kind: KindPair, Total: 10, Upcard: 6, Index: 4, Action: ActionSplit},
+		{Name: "10 v 10", Kind: KindHard, Total: 10, Upcard: 10, Index: 4, Action: ActionDouble},
+		{Name: "12 v 3", Kind: KindHard, Total: 12, Upcard: 3, Index: 2, Action: ActionStand},
+		{Name: "12 v 2", Kind: KindHard, Total: 12, Upcard: 2, Index: 3, Action: ActionStand},
+		{Name: "11 v A", Kind: KindHard, Total: 11, Upcard: 11, Index: 1, Action: ActionDouble},
+		{Name: "9 v 2", Kind: KindHard, Total: 9, Upcard: 2, Index: 1, Action: ActionDouble},
+		{Name: "10 v A", Kind: KindHard, Total: 10, Upcard: 11, Index: 4, Action: ActionDouble},
+		{Name: "9 v 7", Kind: KindHard, Total: 9, Upcard: 7, Index: 3, Action: ActionDouble},
+		{Name: "16 v 9", Kind: KindHard, Total: 16, Upcard: 9, Index: 5, Action: ActionStand},
+		{Name: "13 v 2", Kind: KindHard, Total: 13, Upcard: 2, Index: -1, Below: true, Action: ActionHit},
+		{Name: "12 v 4", Kind: KindHard, Total: 12, Upcard: 4, Index: 0, Below: true, Action: ActionHit},
+		{Name: "12 v 5", Kind: KindHard, Total: 12, Upcard: 5, Index: -2, Below: true, Action: ActionHit},
+		{Name: "12 v 6", Kind: KindHard, Total: 12, Upcard: 6, Index: -1, Below: true, Action: ActionHit},
+		{Name: "13 v 3", Kind: KindHard, Total: 13, Upcard: 3, Index: -2, Below: true, Action: ActionHit},
+	}
+	Fab4 = []IndexPlay{
+		{Name: "14 v 10 surrender", Kind: KindHard, Total: 14, Upcard: 10, Index: 3, Action: ActionSurrender},
+		{Name: "15 v 10 surrender", Kind: KindHard, Total: 15, Upcard: 10, Index: 0, Action: ActionSurrender},
+		{Name: "15 v 9 surrender", Kind: KindHard, Total: 15, Upcard: 9, Index: 2, Action: ActionSurrender},
+		{Name: "15 v A surrender", Kind: KindHard, Total: 15, Upcard: 11, Index: 1, Action: ActionSurrender},
+	}
+)
+
+// DefaultIndexTable returns the built-in Illustrious 18 and Fab 4 indices
+// for systems that publish them. The Fab 4 come first so a surrender takes
+// precedence over the Illustrious 18 stand on 15 v 10.
+func DefaultIndexTable(system CountSystem) (*IndexTable, error) {
+	if system.Name != HiLo.Name {
+		return nil, fmt.Errorf("no built-in index table for %s; load one from a file", system.Name)
+	}
+	plays := make([]IndexPlay, 0, len(Fab4)+len(Illustrious18))
+	plays = append(plays, Fab4...)
+	plays = append(plays, Illustrious18...)
+	return &IndexTable{System: system.Name, Plays: plays}, nil
+}
+
+// LoadIndexTable reads an index table and checks it was built for system,
+// since indices only mean something on the count they were derived from.
+func LoadIndexTable(r io.Reader, system CountSystem) (*IndexTable, error) {
+	var table IndexTable
+	if err := json.NewDecoder(r).Decode(&table); err != nil {
+		return nil, fmt.Errorf("failed to decode index table: %w", err)
+	}
+	if err := table.Validate(system); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (t *IndexTable) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t)
+}
+
+// Validate checks the plays and that the table is for system, named either
+// in full or by its short name.
+func (t *IndexTable) Validate(system CountSystem) error {
+	if named, err := LookupCountSystem(t.System); t.System != system.Name && (err != nil || named.Name != system.Name) {
+		return fmt.Errorf("index table is for %q, not %s", t.System, system.Name)
+	}
+	for i, play := range t.Plays {
+		switch play.Kind {
+		case KindHard, KindSoft, KindPair:
+		default:
+			return fmt.Errorf("index play %d: unknown hand kind %q", i, play.Kind)
+		}
+		if play.Upcard < 2 || play.Upcard > 11 {
+			return fmt.Errorf("index play %d: upcard must be between 2 and 11", i)
+		}
+		if play.Total < 2 || play.Total > 21 {
+			return fmt.Errorf("index play %d: total must be between 2 and 21", i)
+		}
+	}
+	return nil
+}
+
+// covers reports whether the play applies to the situation against upcard,
+// ignoring the count. Pair plays only apply when the hand may be split; hard
+// and soft plays are skipped when splitting is the basic play.
+func (p *IndexPlay) covers(s Situation, upcard int, canSplit, splitting bool) bool {
+	if p.Upcard != upcard {
+		return false
+	}
+	switch p.Kind {
+	case KindPair:
+		return canSplit && s.Pair == p.Total
+	case KindSoft:
+		return !splitting && s.Soft && s.Total == p.Total
+	case KindHard:
+		return !splitting && !s.Soft && s.Total == p.Total
+	default:
+		return false
+	}
+}
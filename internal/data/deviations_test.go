@@ -0,0 +1,59 @@
+package data
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestIndexTableRoundTrip(t *testing.T) {
+	table, err := DefaultIndexTable(HiLo)
+	if err != nil {
+		t.Fatalf("unexpected index table error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := table.Save(&buf); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	loaded, err := LoadIndexTable(&buf, HiLo)
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if len(loaded.Plays) != len(table.Plays) {
+		t.Fatalf("expected %d plays after round trip, got %d", len(table.Plays), len(loaded.Plays))
+	}
+	for i := range table.Plays {
+		if loaded.Plays[i] != table.Plays[i] {
+			t.Errorf("play %d changed in round trip: %+v != %+v", i, loaded.Plays[i], table.Plays[i])
+		}
+	}
+}
+
+func TestLoadIndexTableInvalid(t *testing.T) {
+	inputs := []string{
+		`{"system": "Hi-Lo", "plays": [{"kind": "firm", "total": 16, "upcard": 10, "action": "stand"}]}`,
+		`{"system": "Hi-Lo", "plays": [{"kind": "hard", "total": 16, "upcard": 1, "action": "stand"}]}`,
+		`{"system": "Hi-Lo", "plays": [{"kind": "hard", "total": 16, "upcard": 10, "action": "dance"}]}`,
+		`{"system": "KO", "plays": [{"kind": "hard", "total": 16, "upcard": 10, "action": "stand"}]}`,
+		`{"plays": [{"kind": "hard", "total": 16, "upcard": 10, "action": "stand"}]}`,
+		`{"system": "Hi-Lo", "plays": [{"kind": "insurance", "index": 3}]}`,
+	}
+	for _, input := range inputs {
+		if _, err := LoadIndexTable(strings.NewReader(input), HiLo); err == nil {
+			t.Errorf("expected error loading %s", input)
+		}
+	}
+}
+
+func TestLoadIndexTableShortSystemName(t *testing.T) {
+	input := `{"system": "hilo", "plays": [{"kind": "hard", "total": 16, "upcard": 10, "action": "stand"}]}`
+	if _, err := LoadIndexTable(strings.NewReader(input), HiLo); err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+}
+
+func TestDefaultIndexTableUnknownSystem(t *testing.T) {
+	if _, err := DefaultIndexTable(OmegaII); err == nil {
+		t.Fatal("expected error for system without built-in indices")
+	}
+}
@@ -0,0 +1,120 @@
+package data
+
+import "testing"
+
+func freeBetRules() Rules {
+	rules := DefaultRules()
+	rules.Variant = FreeBet
+	rules.DoubleAfterSplit = true
+	return rules
+}
+
+func TestFreeBetEligibility(t *testing.T) {
+	rules := freeBetRules()
+	tests := []struct {
+		name          string
+		hand          *Hand
+		double, split bool
+	}{
+		{"hard 9", newTestHand(Card{Spades, Five}, Card{Hearts, Four}), true, false},
+		{"pair of fives", newTestHand(Card{Spades, Five}, Card{Hearts, Five}), true, true},
+		{"soft 19", newTestHand(Card{Spades, Ace}, Card{Hearts, Eight}), false, false},
+		{"three-card 11", newTestHand(Card{Spades, Two}, Card{Hearts, Four}, Card{Clubs, Five}), false, false},
+		{"aces", newTestHand(Card{Spades, Ace}, Card{Hearts, Ace}), false, true},
+		{"tens", newTestHand(Card{Spades, Ten}, Card{Hearts, King}), false, false},
+	}
+	for _, test := range tests {
+		if got := rules.FreeDouble(test.hand); got != test.double {
+			t.Errorf("%s: FreeDouble = %v, want %v", test.name, got, test.double)
+		}
+		if got := rules.FreeSplit(test.hand); got != test.split {
+			t.Errorf("%s: FreeSplit = %v, want %v", test.name, got, test.split)
+		}
+	}
+	if DefaultRules().FreeDouble(tests[0].hand) {
+		t.Error("expected no free doubles at a standard table")
+	}
+}
+
+func TestFreeBetPayout(t *testing.T) {
+	player := NewPlayer("Alice", 100)
+	if err := player.PlaceBet(10); err != nil {
+		t.Fatalf("unexpected bet error: %v", err)
+	}
+	hand := player.ActiveHand()
+	hand.AddCard(Card{Spades, Six})
+	hand.AddCard(Card{Hearts, Five})
+	if err := player.FreeDoubleActiveHand(); err != nil {
+		t.Fatalf("unexpected double error: %v", err)
+	}
+	if player.Bankroll() != 90 || hand.Bet() != 20 || hand.FreeBet() != 10 {
+		t.Fatalf("expected a free $10 double, got bankroll %d, bet %d, free %d", player.Bankroll(), hand.Bet(), hand.FreeBet())
+	}
+	player.Payout(hand, OutcomePush)
+	if player.Bankroll() != 100 || hand.FreeBet() != 0 {
+		t.Errorf("expected a push to return only the $10 staked, got bankroll %d", player.Bankroll())
+	}
+}
+
+func TestFreeBetRound(t *testing.T) {
+	game, err := NewGameWithRules(freeBetRules(), []PlayerConfig{{Name: "Alice", Bankroll: 10}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.deck.cards = []Card{
+		{Suit: Spades, Rank: Eight},   // player card 1
+		{Suit: Clubs, Rank: Ten},      // dealer upcard
+		{Suit: Hearts, Rank: Eight},   // player card 2
+		{Suit: Diamonds, Rank: Seven}, // dealer hole card
+		{Suit: Spades, Rank: Three},   // first split hand
+		{Suit: Hearts, Rank: Ten},     // second split hand
+		{Suit: Clubs, Rank: Nine},     // free double on 11
+	}
+	if err := game.StartRound(map[string]int{"Alice": 10}); err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	if err := game.DealInitialCards(); err != nil {
+		t.Fatalf("unexpected deal error: %v", err)
+	}
+	player := game.Players()[0]
+	if _, _, err := game.Split(player); err != nil {
+		t.Fatalf("expected a free split with an empty bankroll, got %v", err)
+	}
+	if _, err := game.DoubleDown(player); err != nil {
+		t.Fatalf("expected a free double with an empty bankroll, got %v", err)
+	}
+	if err := game.Stand(player); err != nil {
+		t.Fatalf("unexpected stand error: %v", err)
+	}
+	hands := player.Hands()
+	if hands[0].FreeBet() != 10 || hands[1].FreeBet() != 10 || player.Bankroll() != 0 {
+		t.Fatalf("expected both extra stakes free, got %d and %d with bankroll %d", hands[0].FreeBet(), hands[1].FreeBet(), player.Bankroll())
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected the round to be ready for the dealer")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer error: %v", err)
+	}
+	if _, err := game.SettleRound(); err != nil {
+		t.Fatalf("unexpected settle error: %v", err)
+	}
+	// 20 beats 17 for $10 staked plus $20 won, and 18 wins $10 on the free
+	// split's bet alone.
+	if player.Bankroll() != 40 {
+		t.Errorf("expected bankroll 40, got %d", player.Bankroll())
+	}
+}
+
+func TestFreeBetDealer22Pushes(t *testing.T) {
+	rules := freeBetRules()
+	dealer := newTestHand(Card{Clubs, Ten}, Card{Clubs, Six}, Card{Hearts, Six})
+	hand := newTestHand(Card{Spades, Ten}, Card{Hearts, Nine})
+	if outcome, _ := rules.settle(hand, dealer, OutcomeWin); outcome != OutcomePush {
+		t.Errorf("expected a dealer 22 to push, got %v", outcome)
+	}
+	natural := newTestHand(Card{Spades, Ace}, Card{Hearts, King})
+	if outcome, _ := rules.settle(natural, dealer, OutcomeBlackjack); outcome != OutcomeBlackjack {
+		t.Errorf("expected a blackjack to beat a dealer 22, got %v", outcome)
+	}
+}
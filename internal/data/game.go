@@ -1,26 +1,41 @@
 package data
 
-import "fmt"
+import (
+	"fmt"
+	"math/rand"
+)
 
 type GameState int
 
 const (
 	StateBetting GameState = iota
 	StateDealing
+	// StateSwitch waits for every Blackjack Switch player to keep or swap
+	// their second cards before the dealer peeks.
+	StateSwitch
 	StatePlayerAction
 	StateDealerAction
 	StateSettled
 )
 
+// PlayerConfig describes a seat. Seats with an Agent bet and play on their
+// own through PlayAgents.
 type PlayerConfig struct {
 	Name     string
 	Bankroll int
+	Agent    Agent
 }
 
+// RoundResult settles one hand. The player's side bets ride on the result
+// for their first hand.
 type RoundResult struct {
 	Player  *Player
 	Hand    *Hand
 	Outcome HandOutcome
+	// Bonus is the Spanish 21 bonus or Pontoon trick the hand was paid, if
+	// any.
+	Bonus    Bonus
+	SideBets []SideBetResult
 }
 
 var (
@@ -29,20 +44,41 @@ var (
 )
 
 type Game struct {
-	deck    *Deck
-	dealer  *Dealer
-	players []*Player
-	state   GameState
+	deck     *Deck
+	dealer   *Dealer
+	players  []*Player
+	state    GameState
+	rules    Rules
+	counter  *Counter
+	rng      *rand.Rand
+	shuffles int
+	// paytables settle the side bets and jackpot pays the progressive.
+	paytables   map[SideBet]Paytable
+	jackpot     *Jackpot
+	jackpotHits []JackpotHit
+
+	// Round history, kept for review until the next round starts. The
+	// buffers are reused between rounds.
+	roundCards []Card
+	decisions  []Decision
+	handStates []HandState
+	stateCards []Card
 }
 
 func NewGame(numDecks int, configs []PlayerConfig) (*Game, error) {
-	if numDecks <= 0 {
-		return nil, fmt.Errorf("number of decks must be positive")
+	rules := DefaultRules()
+	rules.Decks = numDecks
+	return NewGameWithRules(rules, configs)
+}
+
+func NewGameWithRules(rules Rules, configs []PlayerConfig) (*Game, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
 	}
 	if len(configs) == 0 {
 		return nil, fmt.Errorf("at least one player required")
 	}
-	deck := NewDeck(numDecks)
+	deck := NewDeckOf(rules.Decks, rules.Variant.Ranks())
 	deck.Shuffle()
 	players := make([]*Player, len(configs))
 	for i, cfg := range configs {
@@ -50,12 +86,24 @@ func NewGame(numDecks int, configs []PlayerConfig) (*Game, error) {
 			return nil, fmt.Errorf("player %s must start with a positive bankroll", cfg.Name)
 		}
 		players[i] = NewPlayer(cfg.Name, cfg.Bankroll)
+		players[i].agent = cfg.Agent
 	}
+	dealer := NewDealer()
+	dealer.hitSoft17 = rules.DealerHitsSoft17
+	paytables := map[SideBet]Paytable{}
+	for _, bet := range SideBetSpots() {
+		paytables[bet] = DefaultPaytable(bet)
+	}
+	jackpot := DefaultJackpot()
 	return &Game{
-		deck:    deck,
-		dealer:  NewDealer(),
-		players: players,
-		state:   StateBetting,
+		deck:      deck,
+		dealer:    dealer,
+		players:   players,
+		state:     StateBetting,
+		rules:     rules,
+		counter:   NewCounter(HiLo),
+		paytables: paytables,
+		jackpot:   &jackpot,
 	}, nil
 }
 
@@ -75,18 +123,106 @@ func (g *Game) State() GameState {
 	return g.state
 }
 
+func (g *Game) Rules() Rules {
+	return g.rules
+}
+
+// Seed switches the shoe to a deterministic shuffle and starts a fresh shoe,
+// so two games seeded alike deal the same cards to the same decisions.
+func (g *Game) Seed(seed int64) {
+	g.rng = rand.New(rand.NewSource(seed))
+	g.shuffle()
+}
+
+// Shuffles counts how many times the shoe has been rebuilt after the cut
+// card came out.
+func (g *Game) Shuffles() int {
+	return g.shuffles
+}
+
+// NeedsShuffle reports whether the cut card has been reached. The shoe is
+// rebuilt when the settled round is cleared by PrepareNextRound.
+func (g *Game) NeedsShuffle() bool {
+	total := g.rules.Decks * g.rules.DeckSize()
+	dealt := total - g.deck.CardsLeft()
+	return float64(dealt) >= g.rules.Penetration*float64(total)
+}
+
+func (g *Game) shuffle() {
+	g.deck = NewDeckOf(g.rules.Decks, g.rules.Variant.Ranks())
+	g.deck.UseRand(g.rng)
+	g.deck.Shuffle()
+	g.counter.Reset()
+}
+
+func (g *Game) Counter() *Counter {
+	return g.counter
+}
+
+// SetCountSystem switches the live count to a new system, starting it over
+// from zero.
+func (g *Game) SetCountSystem(system CountSystem) {
+	g.counter = NewCounter(system)
+}
+
+// TrueCount is the live true count from the player's point of view: hidden
+// dealer cards are still treated as unseen.
+func (g *Game) TrueCount() float64 {
+	unseen := g.deck.CardsLeft()
+	cards := g.dealer.ActiveHand().Cards()
+	if g.dealer.UpcardHidden() && len(cards) > 0 {
+		unseen++
+	}
+	if g.dealer.HoleCardHidden() && len(cards) > 1 {
+		unseen++
+	}
+	return g.counter.TrueCount(unseen)
+}
+
+// UnseenRanks counts the cards the player has not seen by rank: the rest of
+// the shoe plus hidden dealer cards.
+func (g *Game) UnseenRanks() [14]int {
+	counts := g.deck.RankCounts()
+	cards := g.dealer.ActiveHand().Cards()
+	if g.dealer.UpcardHidden() && len(cards) > 0 {
+		counts[cards[0].Rank]++
+	}
+	if g.dealer.HoleCardHidden() && len(cards) > 1 {
+		counts[cards[1].Rank]++
+	}
+	return counts
+}
+
 func (g *Game) StartRound(bets map[string]int) error {
 	if g.state != StateBetting {
 		return ErrInvalidState
 	}
 	g.dealer.ResetForRound()
+	if g.rules.HoleCardExposed() {
+		g.dealer.RevealHoleCard()
+	}
+	g.dealer.upcardHidden = g.rules.UpcardHidden()
+	g.roundCards = g.roundCards[:0]
+	g.decisions = g.decisions[:0]
+	g.handStates = g.handStates[:0]
+	g.stateCards = g.stateCards[:0]
+	g.jackpotHits = g.jackpotHits[:0]
+	g.dropBrokeAgents()
 	for _, player := range g.players {
 		player.ResetForRound()
+	}
+	for _, player := range g.players {
 		bet, ok := bets[player.Name()]
+		if !ok && player.Agent() != nil {
+			bet, ok = g.agentBet(player), true
+		}
 		if !ok {
 			return fmt.Errorf("missing bet for player %s", player.Name())
 		}
-		if err := player.PlaceBet(bet); err != nil {
+		if err := g.rules.CheckBet(bet); err != nil {
+			return fmt.Errorf("player %s bet failed: %w", player.Name(), err)
+		}
+		if err := player.PlaceBoxes(bet, g.rules.Boxes()); err != nil {
 			return fmt.Errorf("player %s bet failed: %w", player.Name(), err)
 		}
 	}
@@ -100,17 +236,51 @@ func (g *Game) DealInitialCards() error {
 	}
 	for i := 0; i < 2; i++ {
 		for _, player := range g.players {
-			card := g.deck.Deal()
-			player.ActiveHand().AddCard(card)
+			for _, hand := range player.Hands() {
+				hand.AddCard(g.draw())
+			}
+		}
+		// Face-down cards are counted once they are revealed in
+		// DealerPlay. With no hole card the dealer draws a second card
+		// there instead.
+		if i == 1 && g.rules.NoHoleCard {
+			continue
+		}
+		hidden := g.dealer.HoleCardHidden()
+		if i == 0 {
+			hidden = g.dealer.UpcardHidden()
 		}
-		dealerCard := g.deck.Deal()
-		g.dealer.ActiveHand().AddCard(dealerCard)
+		if hidden {
+			g.dealer.ActiveHand().AddCard(g.drawHidden())
+		} else {
+			g.dealer.ActiveHand().AddCard(g.draw())
+		}
+	}
+	g.settleSideBets()
+	if g.rules.Variant == BlackjackSwitch {
+		g.state = StateSwitch
+		g.switchAgents()
+		return nil
 	}
+	g.beginPlay()
+	return nil
+}
+
+// beginPlay has the dealer peek under the upcard; a blackjack ends the
+// round before anyone acts. A dealer without a hole card has nothing to
+// peek at.
+func (g *Game) beginPlay() {
+	dealerBlackjack := g.dealer.ActiveHand().IsBlackjack()
 	for _, player := range g.players {
 		player.SetStatus(PlayerStatusActing)
+		if dealerBlackjack {
+			for _, hand := range player.Hands() {
+				hand.Stand()
+			}
+			player.SetStatus(PlayerStatusStanding)
+		}
 	}
 	g.state = StatePlayerAction
-	return nil
 }
 
 func (g *Game) Hit(player *Player) (Card, error) {
@@ -120,19 +290,145 @@ func (g *Game) Hit(player *Player) (Card, error) {
 	if !g.containsPlayer(player) {
 		return Card{}, ErrUnknownPlayer
 	}
-	card := g.deck.Deal()
 	active := player.ActiveHand()
 	if active == nil {
 		return Card{}, ErrNoActiveHand
 	}
+	if active.IsDoubleDown() && !g.rules.Buys() {
+		return Card{}, ErrHandDoubled
+	}
+	if g.rules.SplitAceDone(active) {
+		return Card{}, ErrSplitAceDone
+	}
+	decision := g.snapshot(player, ActionHit)
+	card := g.draw()
 	active.AddCard(card)
-	if active.IsBusted() {
+	g.decisions = append(g.decisions, decision)
+	if g.handDone(active) {
 		active.Stand()
-		player.SetStatus(PlayerStatusStanding)
+		player.MoveToNextHand()
+	}
+	return card, nil
+}
+
+// DoubleDown doubles the active hand's bet, deals it exactly one card and
+// moves the player on to their next hand. Where a doubled hand can be
+// rescued, it stays active after its card until the player stands or
+// surrenders it, and a Pontoon buy stays open to twist or stick.
+func (g *Game) DoubleDown(player *Player) (Card, error) {
+	if g.state != StatePlayerAction {
+		return Card{}, ErrInvalidState
+	}
+	if !g.containsPlayer(player) {
+		return Card{}, ErrUnknownPlayer
+	}
+	active := player.ActiveHand()
+	if active == nil {
+		return Card{}, ErrNoActiveHand
+	}
+	if active.IsSplit() && !g.rules.DoubleAfterSplit {
+		return Card{}, ErrDoubleNotAllowed
+	}
+	if g.rules.SplitAceDone(active) {
+		return Card{}, ErrSplitAceDone
+	}
+	double := player.DoubleDownActiveHand
+	switch {
+	case g.rules.FreeDouble(active):
+		double = player.FreeDoubleActiveHand
+	case g.rules.LateDouble():
+		double = player.LateDoubleActiveHand
+	}
+	decision := g.snapshot(player, ActionDouble)
+	if err := double(); err != nil {
+		return Card{}, err
+	}
+	card := g.draw()
+	active.AddCard(card)
+	g.decisions = append(g.decisions, decision)
+	if !g.rules.DoubleRescue() && !g.rules.Buys() || g.handDone(active) {
+		active.Stand()
+		player.MoveToNextHand()
 	}
 	return card, nil
 }
 
+// handDone reports whether the card just drawn closes the hand: a bust, or
+// a Charlie where the rules have one.
+func (g *Game) handDone(hand *Hand) bool {
+	charlie := g.rules.CharlieCards()
+	return hand.IsBusted() || charlie > 0 && len(hand.Cards()) >= charlie
+}
+
+// Split splits the active hand and deals a second card to each half. Split
+// aces stand on that card unless the rules let them draw.
+func (g *Game) Split(player *Player) (Card, Card, error) {
+	if g.state != StatePlayerAction {
+		return Card{}, Card{}, ErrInvalidState
+	}
+	if !g.containsPlayer(player) {
+		return Card{}, Card{}, ErrUnknownPlayer
+	}
+	active := player.ActiveHand()
+	if active == nil {
+		return Card{}, Card{}, ErrNoActiveHand
+	}
+	if len(player.Hands()) >= g.rules.MaxHands() {
+		return Card{}, Card{}, ErrSplitNotAllowed
+	}
+	if g.rules.SplitAceDone(active) {
+		return Card{}, Card{}, ErrSplitAceDone
+	}
+	split := player.SplitActiveHand
+	if g.rules.FreeSplit(active) {
+		split = player.FreeSplitActiveHand
+	}
+	decision := g.snapshot(player, ActionSplit)
+	newHand, err := split()
+	if err != nil {
+		return Card{}, Card{}, err
+	}
+	g.decisions = append(g.decisions, decision)
+	first := g.draw()
+	active.AddCard(first)
+	second := g.draw()
+	newHand.AddCard(second)
+	if g.rules.SplitAceDone(active) {
+		active.Stand()
+		newHand.Stand()
+		player.MoveToNextHand()
+	}
+	return first, second, nil
+}
+
+func (g *Game) Surrender(player *Player) error {
+	if g.state != StatePlayerAction {
+		return ErrInvalidState
+	}
+	if !g.containsPlayer(player) {
+		return ErrUnknownPlayer
+	}
+	if active := player.ActiveHand(); active != nil && active.IsDoubleDown() && g.rules.DoubleRescue() {
+		decision := g.snapshot(player, ActionSurrender)
+		if err := player.RescueActiveHand(); err != nil {
+			return err
+		}
+		g.decisions = append(g.decisions, decision)
+		player.MoveToNextHand()
+		return nil
+	}
+	if !g.rules.Surrender {
+		return ErrSurrenderNotAllowed
+	}
+	decision := g.snapshot(player, ActionSurrender)
+	if err := player.SurrenderActiveHand(); err != nil {
+		return err
+	}
+	g.decisions = append(g.decisions, decision)
+	player.MoveToNextHand()
+	return nil
+}
+
 func (g *Game) Stand(player *Player) error {
 	if g.state != StatePlayerAction {
 		return ErrInvalidState
@@ -144,6 +440,10 @@ func (g *Game) Stand(player *Player) error {
 	if active == nil {
 		return ErrNoActiveHand
 	}
+	if active.Value() < g.rules.MinStick() {
+		return ErrStickTooLow
+	}
+	g.decisions = append(g.decisions, g.snapshot(player, ActionStand))
 	active.Stand()
 	if !player.MoveToNextHand() {
 		player.SetStatus(PlayerStatusStanding)
@@ -170,11 +470,19 @@ func (g *Game) DealerPlay() error {
 	if g.state != StateDealerAction {
 		return ErrInvalidState
 	}
+	cards := g.dealer.ActiveHand().Cards()
+	if g.dealer.UpcardHidden() && len(cards) > 0 {
+		g.counter.Observe(cards[0])
+	}
+	if g.dealer.HoleCardHidden() && len(cards) > 1 {
+		g.counter.Observe(cards[1])
+	}
 	g.dealer.RevealHoleCard()
 	for g.dealer.ShouldHit() {
-		card := g.deck.Deal()
-		g.dealer.ActiveHand().AddCard(card)
+		g.dealer.ActiveHand().AddCard(g.draw())
 	}
+	g.settleDealerBust()
+	g.settleProgressive()
 	return nil
 }
 
@@ -188,10 +496,23 @@ func (g *Game) SettleRound() ([]RoundResult, error) {
 	dealerValue := dealerHand.Value()
 	results := make([]RoundResult, 0)
 	for _, player := range g.players {
-		for _, hand := range player.Hands() {
+		for i, hand := range player.Hands() {
 			outcome := determineOutcome(hand, dealerValue, dealerBust, dealerBlackjack)
+			outcome, bonus := g.rules.settle(hand, dealerHand, outcome)
+			if outcome == OutcomeLose && dealerBlackjack && g.rules.OriginalBetsOnly && !hand.IsBusted() {
+				outcome = player.refundExtra(hand)
+			}
+			player.payBonus(hand, bonus)
+			if outcome == OutcomeBlackjack {
+				num, den := g.rules.BlackjackPays()
+				player.payBlackjack(hand, num, den)
+			}
 			player.Payout(hand, outcome)
-			results = append(results, RoundResult{Player: player, Hand: hand, Outcome: outcome})
+			result := RoundResult{Player: player, Hand: hand, Outcome: outcome, Bonus: bonus}
+			if i == 0 {
+				result.SideBets = player.SideBets()
+			}
+			results = append(results, result)
 		}
 	}
 	g.state = StateSettled
@@ -202,6 +523,10 @@ func (g *Game) PrepareNextRound() {
 	if g.state != StateSettled {
 		return
 	}
+	if g.NeedsShuffle() {
+		g.shuffle()
+		g.shuffles++
+	}
 	g.state = StateBetting
 }
 
@@ -209,6 +534,14 @@ func determineOutcome(hand *Hand, dealerValue int, dealerBust, dealerBlackjack b
 	if hand.IsBusted() {
 		return OutcomeLose
 	}
+	// Late surrender is only honoured once the dealer is known not to
+	// hold blackjack.
+	if hand.IsSurrendered() {
+		if dealerBlackjack {
+			return OutcomeLose
+		}
+		return OutcomeSurrender
+	}
 	if hand.IsBlackjack() && !dealerBlackjack {
 		return OutcomeBlackjack
 	}
@@ -228,6 +561,24 @@ func determineOutcome(hand *Hand, dealerValue int, dealerBust, dealerBlackjack b
 	return OutcomePush
 }
 
+func (g *Game) draw() Card {
+	card := g.drawHidden()
+	g.counter.Observe(card)
+	return card
+}
+
+// drawHidden deals a card without counting it. A crowded table can run a
+// shoe dry mid-round; a new one is started rather than panicking.
+func (g *Game) drawHidden() Card {
+	if g.deck.CardsLeft() == 0 {
+		g.shuffle()
+		g.shuffles++
+	}
+	card := g.deck.Deal()
+	g.roundCards = append(g.roundCards, card)
+	return card
+}
+
 func (g *Game) containsPlayer(target *Player) bool {
 	for _, player := range g.players {
 		if player == target {
@@ -76,3 +76,367 @@ func TestGameRoundLifecycle(t *testing.T) {
 		t.Fatalf("expected state StateSettled after settlement, got %v", game.State())
 	}
 }
+
+func newRiggedGame(t *testing.T, rules Rules, cards []Card) (*Game, *Player) {
+	t.Helper()
+	game, err := NewGameWithRules(rules, []PlayerConfig{{Name: "Alice", Bankroll: 100}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.deck.cards = cards
+	if err := game.StartRound(map[string]int{"Alice": 10}); err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	if err := game.DealInitialCards(); err != nil {
+		t.Fatalf("unexpected deal initial cards error: %v", err)
+	}
+	return game, game.Players()[0]
+}
+
+func TestGameSplitAndDoubleDown(t *testing.T) {
+	game, player := newRiggedGame(t, DefaultRules(), []Card{
+		{Suit: Spades, Rank: Eight}, // player card 1
+		{Suit: Clubs, Rank: Ten},    // dealer card 1
+		{Suit: Hearts, Rank: Eight}, // player card 2
+		{Suit: Diamonds, Rank: Seven},
+		{Suit: Spades, Rank: Three}, // first split hand
+		{Suit: Hearts, Rank: Ten},   // second split hand
+		{Suit: Clubs, Rank: Nine},   // double on first hand
+	})
+
+	first, second, err := game.Split(player)
+	if err != nil {
+		t.Fatalf("unexpected split error: %v", err)
+	}
+	if first.Rank != Three || second.Rank != Ten {
+		t.Fatalf("expected split hands to draw 3 and 10, got %v and %v", first, second)
+	}
+	if _, err := game.DoubleDown(player); err != nil {
+		t.Fatalf("unexpected double down error: %v", err)
+	}
+	if player.ActiveHandIndex() != 1 {
+		t.Fatalf("expected double down to move to second hand, got index %d", player.ActiveHandIndex())
+	}
+	if player.Bankroll() != 70 {
+		t.Fatalf("expected bankroll 70 after split and double, got %d", player.Bankroll())
+	}
+	if err := game.Stand(player); err != nil {
+		t.Fatalf("unexpected stand error: %v", err)
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected game to be ready for dealer")
+	}
+}
+
+func TestGameSplitLimitAndNoDAS(t *testing.T) {
+	rules := DefaultRules()
+	rules.MaxSplitHands = 1
+	rules.DoubleAfterSplit = false
+	game, player := newRiggedGame(t, rules, []Card{
+		{Suit: Spades, Rank: Eight},
+		{Suit: Clubs, Rank: Ten},
+		{Suit: Hearts, Rank: Eight},
+		{Suit: Diamonds, Rank: Seven},
+	})
+	if _, _, err := game.Split(player); err != ErrSplitNotAllowed {
+		t.Fatalf("expected split limit error, got %v", err)
+	}
+
+	player.ActiveHand().split = true
+	if _, err := game.DoubleDown(player); err != ErrDoubleNotAllowed {
+		t.Fatalf("expected double after split to be refused, got %v", err)
+	}
+}
+
+func TestGameSplitAcesTakeOneCard(t *testing.T) {
+	cards := []Card{
+		{Suit: Spades, Rank: Ace},
+		{Suit: Clubs, Rank: Ten},
+		{Suit: Hearts, Rank: Ace},
+		{Suit: Diamonds, Rank: Seven},
+		{Suit: Clubs, Rank: Ace}, // first split hand pairs up again
+		{Suit: Spades, Rank: Five},
+		{Suit: Hearts, Rank: Two},
+	}
+	game, player := newRiggedGame(t, DefaultRules(), append([]Card(nil), cards...))
+	if _, _, err := game.Split(player); err != nil {
+		t.Fatalf("unexpected split error: %v", err)
+	}
+	for i, hand := range player.Hands() {
+		if !hand.IsStanding() || len(hand.Cards()) != 2 {
+			t.Fatalf("expected split ace %d to stand on two cards, got %v", i, hand)
+		}
+	}
+	if _, err := game.Hit(player); err != ErrSplitAceDone {
+		t.Errorf("expected a hit on a split ace to be refused, got %v", err)
+	}
+	if _, err := game.DoubleDown(player); err != ErrSplitAceDone {
+		t.Errorf("expected a double on a split ace to be refused, got %v", err)
+	}
+	if _, _, err := game.Split(player); err != ErrSplitAceDone {
+		t.Errorf("expected a resplit of aces to be refused, got %v", err)
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected game to be ready for dealer after splitting aces")
+	}
+
+	rules := DefaultRules()
+	rules.HitSplitAces = true
+	game, player = newRiggedGame(t, rules, cards)
+	if _, _, err := game.Split(player); err != nil {
+		t.Fatalf("unexpected split error: %v", err)
+	}
+	if _, _, err := game.Split(player); err != nil {
+		t.Fatalf("expected aces to resplit, got %v", err)
+	}
+	if player.ActiveHand().IsStanding() {
+		t.Fatal("expected split aces to stay open")
+	}
+	if _, err := game.Hit(player); err != nil {
+		t.Fatalf("expected split aces to draw, got %v", err)
+	}
+}
+
+func TestGameSurrender(t *testing.T) {
+	cards := []Card{
+		{Suit: Spades, Rank: Ten},
+		{Suit: Clubs, Rank: Ten},
+		{Suit: Hearts, Rank: Six},
+		{Suit: Diamonds, Rank: Seven},
+	}
+	game, player := newRiggedGame(t, DefaultRules(), append([]Card(nil), cards...))
+	if err := game.Surrender(player); err != ErrSurrenderNotAllowed {
+		t.Fatalf("expected surrender to be refused by default rules, got %v", err)
+	}
+
+	rules := DefaultRules()
+	rules.Surrender = true
+	game, player = newRiggedGame(t, rules, cards)
+	if err := game.Surrender(player); err != nil {
+		t.Fatalf("unexpected surrender error: %v", err)
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected game to be ready for dealer after surrender")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer play error: %v", err)
+	}
+	results, err := game.SettleRound()
+	if err != nil {
+		t.Fatalf("unexpected settle round error: %v", err)
+	}
+	if results[0].Outcome != OutcomeSurrender {
+		t.Fatalf("expected surrender outcome, got %v", results[0].Outcome)
+	}
+	if player.Bankroll() != 95 {
+		t.Fatalf("expected bankroll 95 after surrender, got %d", player.Bankroll())
+	}
+}
+
+func TestGameCountsHoleCardOnReveal(t *testing.T) {
+	game, player := newRiggedGame(t, DefaultRules(), []Card{
+		{Suit: Spades, Rank: Two},   // +1
+		{Suit: Clubs, Rank: Three},  // +1
+		{Suit: Hearts, Rank: Four},  // +1
+		{Suit: Diamonds, Rank: Ten}, // hole card, -1 once revealed
+		{Suit: Spades, Rank: Five},  // dealer draw, +1
+	})
+	if got := game.Counter().Running(); got != 3 {
+		t.Fatalf("expected running count 3 with hole card hidden, got %d", got)
+	}
+	if err := game.Stand(player); err != nil {
+		t.Fatalf("unexpected stand error: %v", err)
+	}
+	game.ReadyForDealer()
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer play error: %v", err)
+	}
+	if got := game.Counter().Running(); got != 3 {
+		t.Fatalf("expected running count 3 after reveal and draw, got %d", got)
+	}
+	if got := game.Counter().Seen(); got != 5 {
+		t.Fatalf("expected 5 cards seen, got %d", got)
+	}
+}
+
+func TestGameUnseenRanksIncludesHoleCard(t *testing.T) {
+	game, _ := newRiggedGame(t, DefaultRules(), []Card{
+		{Suit: Spades, Rank: Two},
+		{Suit: Clubs, Rank: Three},
+		{Suit: Hearts, Rank: Four},
+		{Suit: Diamonds, Rank: Ten}, // hole card
+		{Suit: Spades, Rank: Five},
+		{Suit: Hearts, Rank: Five},
+	})
+	want := [14]int{}
+	want[Ten] = 1
+	want[Five] = 2
+	if got := game.UnseenRanks(); got != want {
+		t.Fatalf("unseen ranks = %v, want %v", got, want)
+	}
+	if got := game.Deck().RankCounts()[Ten]; got != 0 {
+		t.Fatalf("expected the hole card to be out of the deck, got %d tens", got)
+	}
+}
+
+func TestGameDealerPeekEndsRound(t *testing.T) {
+	game, player := newRiggedGame(t, DefaultRules(), []Card{
+		{Suit: Spades, Rank: Nine},
+		{Suit: Clubs, Rank: Ace},
+		{Suit: Hearts, Rank: Nine},
+		{Suit: Diamonds, Rank: King},
+	})
+	if !player.ActiveHand().IsStanding() {
+		t.Fatal("expected player hand to stand once the dealer shows blackjack")
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected round to go straight to the dealer")
+	}
+}
+
+func TestGameNoHoleCard(t *testing.T) {
+	for _, obo := range []bool{false, true} {
+		rules := DefaultRules()
+		rules.NoHoleCard = true
+		rules.OriginalBetsOnly = obo
+		game, player := newRiggedGame(t, rules, []Card{
+			{Suit: Spades, Rank: Eight}, // player card 1
+			{Suit: Clubs, Rank: Ace},    // dealer upcard, no peek
+			{Suit: Hearts, Rank: Eight}, // player card 2
+			{Suit: Spades, Rank: Three}, // first split hand
+			{Suit: Hearts, Rank: Ten},   // second split hand
+			{Suit: Clubs, Rank: Nine},   // double on first hand
+			{Suit: Diamonds, Rank: King},
+		})
+		if cards := game.Dealer().ActiveHand().Cards(); len(cards) != 1 {
+			t.Fatalf("expected the dealer to hold only the upcard, got %v", cards)
+		}
+		if _, _, err := game.Split(player); err != nil {
+			t.Fatalf("unexpected split error: %v", err)
+		}
+		if _, err := game.DoubleDown(player); err != nil {
+			t.Fatalf("unexpected double down error: %v", err)
+		}
+		if err := game.Stand(player); err != nil {
+			t.Fatalf("unexpected stand error: %v", err)
+		}
+		if !game.ReadyForDealer() {
+			t.Fatal("expected game to be ready for dealer")
+		}
+		if err := game.DealerPlay(); err != nil {
+			t.Fatalf("unexpected dealer play error: %v", err)
+		}
+		if !game.Dealer().ActiveHand().IsBlackjack() {
+			t.Fatalf("expected the dealer to draw to blackjack, got %v", game.Dealer().ActiveHand())
+		}
+		results, err := game.SettleRound()
+		if err != nil {
+			t.Fatalf("unexpected settle round error: %v", err)
+		}
+		want, second := 70, OutcomeLose
+		if obo {
+			want, second = 90, OutcomePush
+		}
+		if player.Bankroll() != want || results[0].Outcome != OutcomeLose || results[1].Outcome != second {
+			t.Errorf("obo %v: expected bankroll %d, got %d with outcomes %v and %v", obo, want, player.Bankroll(), results[0].Outcome, results[1].Outcome)
+		}
+	}
+}
+
+func TestGameNoHoleCardBustsLoseInFull(t *testing.T) {
+	rules := DefaultRules()
+	rules.NoHoleCard = true
+	rules.OriginalBetsOnly = true
+	game, player := newRiggedGame(t, rules, []Card{
+		{Suit: Spades, Rank: Six},    // player card 1
+		{Suit: Clubs, Rank: Ace},     // dealer upcard, no peek
+		{Suit: Hearts, Rank: Six},    // player card 2
+		{Suit: Spades, Rank: Five},   // first split hand makes 11
+		{Suit: Hearts, Rank: Ten},    // second split hand makes 16
+		{Suit: Clubs, Rank: Two},     // double on 11 to 13
+		{Suit: Diamonds, Rank: Ten},  // second hand busts
+		{Suit: Diamonds, Rank: King}, // dealer draws to blackjack
+	})
+	if _, _, err := game.Split(player); err != nil {
+		t.Fatalf("unexpected split error: %v", err)
+	}
+	if _, err := game.DoubleDown(player); err != nil {
+		t.Fatalf("unexpected double down error: %v", err)
+	}
+	if _, err := game.Hit(player); err != nil {
+		t.Fatalf("unexpected hit error: %v", err)
+	}
+	hands := player.Hands()
+	if !hands[1].IsBusted() {
+		t.Fatalf("expected the split hand to bust, got %v", hands[1])
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected game to be ready for dealer")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer play error: %v", err)
+	}
+	results, err := game.SettleRound()
+	if err != nil {
+		t.Fatalf("unexpected settle round error: %v", err)
+	}
+	// The doubled hand gets its double back; the busted split hand loses.
+	if results[0].Outcome != OutcomeLose || results[1].Outcome != OutcomeLose || player.Bankroll() != 80 {
+		t.Errorf("expected only the double refunded, got bankroll %d with outcomes %v and %v", player.Bankroll(), results[0].Outcome, results[1].Outcome)
+	}
+
+	game, player = newRiggedGame(t, rules, []Card{
+		{Suit: Spades, Rank: Ten},    // player card 1
+		{Suit: Clubs, Rank: Ace},     // dealer upcard, no peek
+		{Suit: Hearts, Rank: Six},    // player card 2
+		{Suit: Spades, Rank: Queen},  // double on 16 busts
+		{Suit: Diamonds, Rank: King}, // dealer draws to blackjack
+	})
+	if _, err := game.DoubleDown(player); err != nil {
+		t.Fatalf("unexpected double down error: %v", err)
+	}
+	if !player.Hands()[0].IsBusted() {
+		t.Fatalf("expected the doubled hand to bust, got %v", player.Hands()[0])
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected game to be ready for dealer")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer play error: %v", err)
+	}
+	if _, err := game.SettleRound(); err != nil {
+		t.Fatalf("unexpected settle round error: %v", err)
+	}
+	if player.Bankroll() != 80 {
+		t.Errorf("expected a busted double to lose both bets, got bankroll %d", player.Bankroll())
+	}
+}
+
+func TestGameSeedAndReshuffle(t *testing.T) {
+	rules := DefaultRules()
+	rules.Decks = 1
+	rules.Penetration = 0.5
+	first, err := NewGameWithRules(rules, []PlayerConfig{{Name: "Alice", Bankroll: 1000}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	second, _ := NewGameWithRules(rules, []PlayerConfig{{Name: "Alice", Bankroll: 1000}})
+	first.Seed(9)
+	second.Seed(9)
+	for i := range first.deck.cards {
+		if first.deck.cards[i] != second.deck.cards[i] {
+			t.Fatal("expected equally seeded games to shuffle identically")
+		}
+	}
+
+	first.deck.cards = first.deck.cards[:20]
+	first.counter.Observe(Card{Suit: Spades, Rank: Two})
+	first.state = StateSettled
+	first.PrepareNextRound()
+	if first.Shuffles() != 1 || first.Deck().CardsLeft() != 52 {
+		t.Fatalf("expected a fresh shoe past the cut card, got %d shuffles and %d cards", first.Shuffles(), first.Deck().CardsLeft())
+	}
+	if first.Counter().Running() != 0 {
+		t.Fatal("expected the count to restart with the new shoe")
+	}
+}
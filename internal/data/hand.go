@@ -6,10 +6,14 @@ import (
 )
 
 type Hand struct {
-	cards   []Card
-	bet     int
-	stood   bool
-	doubled bool
+	cards       []Card
+	bet         int
+	free        int
+	extra       int
+	stood       bool
+	doubled     bool
+	split       bool
+	surrendered bool
 }
 
 func NewHand() *Hand {
@@ -32,6 +36,12 @@ func (h *Hand) SetBet(amount int) {
 	h.bet = amount
 }
 
+// FreeBet is the part of the bet the house funded with free doubles and
+// splits. It wins like the rest of the bet but is never returned.
+func (h *Hand) FreeBet() int {
+	return h.free
+}
+
 func (h *Hand) Stand() {
 	h.stood = true
 }
@@ -52,10 +62,55 @@ func (h *Hand) DoubleDown() error {
 	return nil
 }
 
+// DoubleDownLate doubles a hand of two or more cards and leaves it open, so
+// after its card the player still chooses to stand or rescue it.
+func (h *Hand) DoubleDownLate() error {
+	if h.doubled {
+		return fmt.Errorf("hand already doubled down")
+	}
+	if len(h.cards) < 2 || h.stood {
+		return fmt.Errorf("double down requires an open hand of two or more cards")
+	}
+	h.doubled = true
+	return nil
+}
+
 func (h *Hand) IsDoubleDown() bool {
 	return h.doubled
 }
 
+func (h *Hand) Surrender() error {
+	if h.surrendered {
+		return fmt.Errorf("hand already surrendered")
+	}
+	if len(h.cards) != 2 || h.split {
+		return fmt.Errorf("surrender requires an unsplit two-card hand")
+	}
+	h.surrendered = true
+	h.stood = true
+	return nil
+}
+
+func (h *Hand) IsSurrendered() bool {
+	return h.surrendered
+}
+
+// Rescue surrenders an open doubled hand: the double comes back and the
+// original bet is lost.
+func (h *Hand) Rescue() error {
+	if !h.doubled || h.stood {
+		return fmt.Errorf("rescue requires an open doubled hand")
+	}
+	h.surrendered = true
+	h.stood = true
+	return nil
+}
+
+// IsSplit reports whether the hand was created by splitting a pair.
+func (h *Hand) IsSplit() bool {
+	return h.split
+}
+
 func (h *Hand) CanSplit() bool {
 	if len(h.cards) != 2 {
 		return false
@@ -78,9 +133,12 @@ func (h *Hand) Split() (*Hand, error) {
 	// Reset flags for the original hand after splitting.
 	h.stood = false
 	h.doubled = false
+	h.split = true
 	newHand := NewHand()
 	newHand.cards = append(newHand.cards, second)
 	newHand.bet = h.bet
+	newHand.extra = h.bet
+	newHand.split = true
 	return newHand, nil
 }
 
@@ -106,8 +164,10 @@ func (h *Hand) Value() int {
 	return value
 }
 
+// IsBlackjack reports a natural: 21 on the first two cards of a hand that
+// did not come from a split.
 func (h *Hand) IsBlackjack() bool {
-	return len(h.cards) == 2 && h.Value() == 21
+	return len(h.cards) == 2 && !h.split && h.Value() == 21
 }
 
 func (h *Hand) IsBusted() bool {
@@ -147,6 +207,10 @@ func (h *Hand) String() string {
 func (h *Hand) Clear() {
 	h.cards = h.cards[:0]
 	h.bet = 0
+	h.free = 0
+	h.extra = 0
 	h.stood = false
 	h.doubled = false
+	h.split = false
+	h.surrendered = false
 }
@@ -75,3 +75,21 @@ func TestHandSoftness(t *testing.T) {
 		t.Fatal("expected hard hand after adding Ten")
 	}
 }
+
+func TestHandSplit21IsNotBlackjack(t *testing.T) {
+	hand := NewHand()
+	hand.AddCard(Card{Suit: Spades, Rank: Ace})
+	hand.AddCard(Card{Suit: Hearts, Rank: Ace})
+	second, err := hand.Split()
+	if err != nil {
+		t.Fatalf("unexpected split error: %v", err)
+	}
+	hand.AddCard(Card{Suit: Clubs, Rank: King})
+	second.AddCard(Card{Suit: Diamonds, Rank: Queen})
+	if hand.IsBlackjack() || second.IsBlackjack() {
+		t.Fatal("expected split 21s not to count as blackjacks")
+	}
+	if hand.Value() != 21 {
+		t.Fatalf("expected a split 21, got %d", hand.Value())
+	}
+}
@@ -0,0 +1,57 @@
+package data
+
+// HandState is a snapshot of a hand taken before a decision.
+type HandState struct {
+	Cards    []Card
+	Bet      int
+	Split    bool
+	Doubled  bool
+	Standing bool
+}
+
+// Decision records one play made during a round so the round can be
+// reviewed once it is settled.
+type Decision struct {
+	Player *Player
+	// Hands holds the player's hands as they were before the play.
+	Hands  []HandState
+	Active int
+	Action Action
+	// Drawn is how many cards had been dealt in the round before the play.
+	Drawn int
+}
+
+// Decisions returns the plays made in the current or last round, in order.
+// The result is only valid until the next round starts.
+func (g *Game) Decisions() []Decision {
+	return g.decisions
+}
+
+// RoundCards returns the cards dealt in the current or last round in the
+// order they left the shoe, including the hole card. The result is only
+// valid until the next round starts.
+func (g *Game) RoundCards() []Card {
+	return g.roundCards
+}
+
+func (g *Game) snapshot(player *Player, action Action) Decision {
+	start := len(g.handStates)
+	for _, hand := range player.Hands() {
+		from := len(g.stateCards)
+		g.stateCards = append(g.stateCards, hand.Cards()...)
+		g.handStates = append(g.handStates, HandState{
+			Cards:    g.stateCards[from:len(g.stateCards):len(g.stateCards)],
+			Bet:      hand.Bet(),
+			Split:    hand.IsSplit(),
+			Doubled:  hand.IsDoubleDown(),
+			Standing: hand.IsStanding() || hand.IsBusted(),
+		})
+	}
+	return Decision{
+		Player: player,
+		Hands:  g.handStates[start:len(g.handStates):len(g.handStates)],
+		Active: player.ActiveHandIndex(),
+		Action: action,
+		Drawn:  len(g.roundCards),
+	}
+}
@@ -22,13 +22,23 @@ const (
 	OutcomePush
 	OutcomeWin
 	OutcomeBlackjack
+	OutcomeSurrender
+	// OutcomeCharlie wins even money on a hand that drew the rules'
+	// Charlie without busting.
+	OutcomeCharlie
 )
 
 var (
 	ErrInvalidBet           = errors.New("bet must be greater than zero")
+	ErrBetOutOfRange        = errors.New("bet outside table limits")
 	ErrInsufficientBankroll = errors.New("insufficient bankroll to complete action")
 	ErrNoActiveHand         = errors.New("no active hand available")
 	ErrSplitNotAllowed      = errors.New("active hand cannot be split")
+	ErrDoubleNotAllowed     = errors.New("active hand cannot be doubled")
+	ErrSurrenderNotAllowed  = errors.New("active hand cannot be surrendered")
+	ErrHandDoubled          = errors.New("doubled hand takes no more cards")
+	ErrSplitAceDone         = errors.New("split aces take one card each")
+	ErrStickTooLow          = errors.New("hand is too low to stick")
 )
 
 type Player struct {
@@ -37,6 +47,8 @@ type Player struct {
 	hands    []*Hand
 	active   int
 	status   PlayerStatus
+	agent    Agent
+	sideBets []SideBetResult
 }
 
 func NewPlayer(name string, bankroll int) *Player {
@@ -53,6 +65,11 @@ func (p *Player) Name() string {
 	return p.name
 }
 
+// Agent returns the agent that plays this seat, or nil for a human.
+func (p *Player) Agent() Agent {
+	return p.agent
+}
+
 func (p *Player) Bankroll() int {
 	return p.bankroll
 }
@@ -100,6 +117,7 @@ func (p *Player) ResetForRound() {
 	p.hands = []*Hand{NewHand()}
 	p.active = 0
 	p.status = PlayerStatusWaiting
+	p.sideBets = nil
 }
 
 func (p *Player) PlaceBet(amount int) error {
@@ -118,7 +136,36 @@ func (p *Player) PlaceBet(amount int) error {
 	return nil
 }
 
+// PlaceBoxes bets amount on each of boxes hands, as Blackjack Switch deals
+// two boxes to every player.
+func (p *Player) PlaceBoxes(amount, boxes int) error {
+	if amount <= 0 {
+		return ErrInvalidBet
+	}
+	if amount*boxes > p.bankroll {
+		return ErrInsufficientBankroll
+	}
+	for len(p.hands) < boxes {
+		p.hands = append(p.hands, NewHand())
+	}
+	for _, hand := range p.hands {
+		hand.SetBet(amount)
+	}
+	p.bankroll -= amount * boxes
+	return nil
+}
+
 func (p *Player) SplitActiveHand() (*Hand, error) {
+	return p.splitActiveHand(false)
+}
+
+// FreeSplitActiveHand splits the active hand with the house funding the
+// new hand's bet.
+func (p *Player) FreeSplitActiveHand() (*Hand, error) {
+	return p.splitActiveHand(true)
+}
+
+func (p *Player) splitActiveHand(free bool) (*Hand, error) {
 	hand := p.ActiveHand()
 	if hand == nil {
 		return nil, ErrNoActiveHand
@@ -126,14 +173,18 @@ func (p *Player) SplitActiveHand() (*Hand, error) {
 	if !hand.CanSplit() {
 		return nil, ErrSplitNotAllowed
 	}
-	if hand.Bet() > p.bankroll {
+	if !free && hand.Bet() > p.bankroll {
 		return nil, ErrInsufficientBankroll
 	}
 	newHand, err := hand.Split()
 	if err != nil {
 		return nil, err
 	}
-	p.bankroll -= hand.Bet()
+	if free {
+		newHand.free = newHand.bet
+	} else {
+		p.bankroll -= hand.Bet()
+	}
 	p.hands = append(p.hands, nil)
 	copy(p.hands[p.active+2:], p.hands[p.active+1:])
 	p.hands[p.active+1] = newHand
@@ -141,6 +192,22 @@ func (p *Player) SplitActiveHand() (*Hand, error) {
 }
 
 func (p *Player) DoubleDownActiveHand() error {
+	return p.doubleActiveHand((*Hand).DoubleDown, false)
+}
+
+// FreeDoubleActiveHand doubles the active hand with the house funding the
+// extra stake.
+func (p *Player) FreeDoubleActiveHand() error {
+	return p.doubleActiveHand((*Hand).DoubleDown, true)
+}
+
+// LateDoubleActiveHand doubles the active hand on any number of cards and
+// leaves it open for a rescue.
+func (p *Player) LateDoubleActiveHand() error {
+	return p.doubleActiveHand((*Hand).DoubleDownLate, false)
+}
+
+func (p *Player) doubleActiveHand(double func(*Hand) error, free bool) error {
 	hand := p.ActiveHand()
 	if hand == nil {
 		return ErrNoActiveHand
@@ -149,47 +216,110 @@ func (p *Player) DoubleDownActiveHand() error {
 	if bet == 0 {
 		return ErrInvalidBet
 	}
-	if bet > p.bankroll {
+	if !free && bet > p.bankroll {
 		return ErrInsufficientBankroll
 	}
-	if err := hand.DoubleDown(); err != nil {
+	if err := double(hand); err != nil {
 		return err
 	}
-	p.bankroll -= bet
+	if free {
+		hand.free += bet
+	} else {
+		p.bankroll -= bet
+	}
+	hand.extra += bet
 	hand.SetBet(bet * 2)
 	return nil
 }
 
+// SurrenderActiveHand gives up the active hand. Each Blackjack Switch box
+// may surrender on its own, but no hand of a split may.
+func (p *Player) SurrenderActiveHand() error {
+	hand := p.ActiveHand()
+	if hand == nil {
+		return ErrNoActiveHand
+	}
+	if hand.IsSplit() {
+		return ErrSurrenderNotAllowed
+	}
+	return hand.Surrender()
+}
+
+// RescueActiveHand takes back the double on the active hand and forfeits
+// the original bet.
+func (p *Player) RescueActiveHand() error {
+	hand := p.ActiveHand()
+	if hand == nil {
+		return ErrNoActiveHand
+	}
+	return hand.Rescue()
+}
+
+// payBonus pays the part of a bonus above even money; Payout pays the rest.
+func (p *Player) payBonus(hand *Hand, bonus Bonus) {
+	num, den := bonus.Pays()
+	p.bankroll += hand.Bet()*num/den - hand.Bet()
+}
+
+// payBlackjack pays a blackjack at num to den in place of the 3:2 Payout
+// adds.
+func (p *Player) payBlackjack(hand *Hand, num, den int) {
+	p.bankroll += hand.Bet()*num/den - hand.Bet()*3/2
+}
+
+// refundExtra returns the stakes a hand added by doubling or splitting
+// when a dealer blackjack takes only the original bets. A hand split off
+// another has no original bet, so it pushes. A busted hand lost before the
+// dealer drew and gets nothing back.
+func (p *Player) refundExtra(hand *Hand) HandOutcome {
+	if hand.extra == hand.Bet() {
+		return OutcomePush
+	}
+	p.bankroll += hand.extra - hand.free
+	return OutcomeLose
+}
+
+// Payout returns the player's own stake on a hand that did not lose, with
+// its winnings. Free stakes earn winnings but go back to the house.
 func (p *Player) Payout(hand *Hand, outcome HandOutcome) {
+	stake := hand.Bet() - hand.FreeBet()
 	switch outcome {
 	case OutcomeLose:
 		// Bet already removed from bankroll during PlaceBet.
 	case OutcomePush:
-		p.bankroll += hand.Bet()
-	case OutcomeWin:
-		p.bankroll += hand.Bet() * 2
+		p.bankroll += stake
+	case OutcomeWin, OutcomeCharlie:
+		p.bankroll += stake + hand.Bet()
 	case OutcomeBlackjack:
-		p.bankroll += hand.Bet() + (hand.Bet()*3)/2
+		p.bankroll += stake + (hand.Bet()*3)/2
+	case OutcomeSurrender:
+		p.bankroll += stake / 2
 	}
 	hand.SetBet(0)
+	hand.free = 0
+	hand.extra = 0
 	p.status = PlayerStatusSettled
 }
 
 type Dealer struct {
 	*Player
 	holeCardHidden bool
+	upcardHidden   bool
+	hitSoft17      bool
 }
 
 func NewDealer() *Dealer {
 	return &Dealer{
 		Player:         NewPlayer("Dealer", 0),
 		holeCardHidden: true,
+		hitSoft17:      true,
 	}
 }
 
 func (d *Dealer) ResetForRound() {
 	d.Player.ResetForRound()
 	d.holeCardHidden = true
+	d.upcardHidden = false
 }
 
 func (d *Dealer) ShouldHit() bool {
@@ -197,11 +327,15 @@ func (d *Dealer) ShouldHit() bool {
 	if hand == nil {
 		return false
 	}
-	value := hand.Value()
+	return DealerShouldHit(hand.Value(), hand.IsSoft(), d.hitSoft17)
+}
+
+// DealerShouldHit is the dealer's drawing rule for a hand of the given value.
+func DealerShouldHit(value int, soft, hitSoft17 bool) bool {
 	if value < 17 {
 		return true
 	}
-	return value == 17 && hand.IsSoft()
+	return value == 17 && hitSoft17 && soft
 }
 
 func (d *Dealer) ShowFirstCard() string {
@@ -213,14 +347,25 @@ func (d *Dealer) ShowFirstCard() string {
 	if len(cards) == 0 {
 		return ""
 	}
+	if d.upcardHidden && len(cards) > 1 {
+		return "[?] [?]"
+	}
 	if d.holeCardHidden && len(cards) > 1 {
 		return cards[0].String() + " [?]"
 	}
 	return hand.String()
 }
 
+// RevealHoleCard turns the dealer's cards face up, the upcard too where it
+// was dealt face down.
 func (d *Dealer) RevealHoleCard() {
 	d.holeCardHidden = false
+	d.upcardHidden = false
+}
+
+// UpcardHidden reports whether the dealer's first card is still face down.
+func (d *Dealer) UpcardHidden() bool {
+	return d.upcardHidden
 }
 
 func (d *Dealer) HoleCardHidden() bool {
@@ -0,0 +1,101 @@
+package data
+
+import "testing"
+
+func pontoonRules() Rules {
+	rules := DefaultRules()
+	rules.Variant = Pontoon
+	return rules
+}
+
+func TestPontoonRound(t *testing.T) {
+	game, player := newRiggedGame(t, pontoonRules(), []Card{
+		{Suit: Spades, Rank: Five},    // player card 1
+		{Suit: Clubs, Rank: Ten},      // dealer card 1, face down
+		{Suit: Hearts, Rank: Four},    // player card 2
+		{Suit: Diamonds, Rank: Seven}, // dealer card 2, face down
+		{Suit: Spades, Rank: Two},     // buy
+		{Suit: Hearts, Rank: Three},   // twist
+		{Suit: Clubs, Rank: Two},      // twist to a five-card trick
+	})
+	if got := game.Dealer().ShowFirstCard(); got != "[?] [?]" {
+		t.Fatalf("expected both dealer cards face down, got %q", got)
+	}
+	if view := game.View(player); view.Upcard.Rank != 0 {
+		t.Fatalf("expected the view to hide the upcard, got %v", view.Upcard)
+	}
+	if got := game.Counter().Running(); got != 2 {
+		t.Fatalf("expected only the player's cards counted, got running count %d", got)
+	}
+
+	if _, err := game.DoubleDown(player); err != nil {
+		t.Fatalf("unexpected buy error: %v", err)
+	}
+	hand := player.ActiveHand()
+	if hand.IsStanding() || hand.Bet() != 20 {
+		t.Fatalf("expected the bought hand open with a $20 stake, got standing %v and bet %d", hand.IsStanding(), hand.Bet())
+	}
+	if _, err := game.Hit(player); err != nil {
+		t.Fatalf("unexpected twist error: %v", err)
+	}
+	if err := game.Stand(player); err != ErrStickTooLow {
+		t.Fatalf("expected sticking on 14 to be refused, got %v", err)
+	}
+	if _, err := game.Hit(player); err != nil {
+		t.Fatalf("unexpected twist error: %v", err)
+	}
+	if !hand.IsStanding() {
+		t.Fatal("expected the fifth card to end the hand")
+	}
+
+	if !game.ReadyForDealer() {
+		t.Fatal("expected game to be ready for dealer")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer play error: %v", err)
+	}
+	results, err := game.SettleRound()
+	if err != nil {
+		t.Fatalf("unexpected settle round error: %v", err)
+	}
+	if results[0].Outcome != OutcomeCharlie || results[0].Bonus != FiveCardTrick {
+		t.Fatalf("expected a five-card trick, got %v with %v", results[0].Outcome, results[0].Bonus)
+	}
+	if player.Bankroll() != 140 {
+		t.Fatalf("expected the trick to pay 2:1 on $20, got bankroll %d", player.Bankroll())
+	}
+}
+
+func TestPontoonDealerWinsTies(t *testing.T) {
+	rules := pontoonRules()
+	pontoon := newTestHand(Card{Spades, Ace}, Card{Hearts, King})
+	dealer := newTestHand(Card{Clubs, Ace}, Card{Clubs, Queen})
+	if outcome, _ := rules.settle(pontoon, dealer, OutcomePush); outcome != OutcomeLose {
+		t.Errorf("expected tied pontoons to lose, got %v", outcome)
+	}
+	if num, den := rules.BlackjackPays(); num != 2 || den != 1 {
+		t.Errorf("expected a pontoon to pay 2:1, got %d:%d", num, den)
+	}
+}
+
+func TestAdvisorPontoon(t *testing.T) {
+	advisor := NewAdvisor(pontoonRules())
+	tests := []struct {
+		name     string
+		hand     *Hand
+		expected Action
+	}{
+		{"14", newTestHand(Card{Spades, Ten}, Card{Hearts, Four}), ActionHit},
+		{"15", newTestHand(Card{Spades, Ten}, Card{Hearts, Five}), ActionStand},
+		{"soft 18", newTestHand(Card{Spades, Ace}, Card{Hearts, Seven}), ActionHit},
+		{"10", newTestHand(Card{Spades, Six}, Card{Hearts, Four}), ActionDouble},
+		{"aces", newTestHand(Card{Spades, Ace}, Card{Hearts, Ace}), ActionSplit},
+		{"four-card 17", newTestHand(Card{Spades, Two}, Card{Hearts, Three}, Card{Clubs, Five}, Card{Clubs, Seven}), ActionHit},
+	}
+	for _, test := range tests {
+		// The upcard is face down, so the advisor gets none.
+		if got := advisor.Recommend(test.hand, Card{}, true, 0); got != test.expected {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.expected)
+		}
+	}
+}
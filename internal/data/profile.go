@@ -0,0 +1,35 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Profile is what the player keeps from one session to the next.
+type Profile struct {
+	Jackpot Jackpot `json:"jackpot"`
+}
+
+func NewProfile() *Profile {
+	return &Profile{Jackpot: DefaultJackpot()}
+}
+
+// LoadProfile reads a saved profile. Settings missing from it keep their
+// defaults.
+func LoadProfile(r io.Reader) (*Profile, error) {
+	profile := NewProfile()
+	if err := json.NewDecoder(r).Decode(profile); err != nil {
+		return nil, fmt.Errorf("failed to decode profile: %w", err)
+	}
+	if profile.Jackpot.Ante <= 0 {
+		return nil, fmt.Errorf("progressive ante must be positive")
+	}
+	return profile, nil
+}
+
+func (p *Profile) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
@@ -0,0 +1,250 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Progression sizes each bet from the results of the bets before it,
+// ignoring the count. The zero value of each progression starts a fresh
+// session, so only Unit needs setting.
+type Progression interface {
+	// Bet returns the next wager in dollars before table and bankroll caps.
+	Bet() int
+	// Record moves the progression on by the net result of the last round:
+	// a win when positive, a loss when negative and a push when zero.
+	Record(net int)
+	// Reset starts a new session.
+	Reset()
+	String() string
+}
+
+// maxSteps keeps doubling progressions from overflowing; any table maximum
+// is reached long before.
+const maxSteps = 40
+
+// FlatBet bets one unit every round.
+type FlatBet struct {
+	Unit int
+}
+
+func (p *FlatBet) Bet() int {
+	return p.Unit
+}
+
+func (p *FlatBet) Record(int) {}
+
+func (p *FlatBet) Reset() {}
+
+func (p *FlatBet) String() string {
+	return fmt.Sprintf("flat $%d", p.Unit)
+}
+
+// Martingale doubles the bet after every loss and drops back to one unit
+// after a win.
+type Martingale struct {
+	Unit   int
+	losses int
+}
+
+func (p *Martingale) Bet() int {
+	return p.Unit << p.losses
+}
+
+func (p *Martingale) Record(net int) {
+	switch {
+	case net < 0:
+		p.losses = min(p.losses+1, maxSteps)
+	case net > 0:
+		p.losses = 0
+	}
+}
+
+func (p *Martingale) Reset() {
+	p.losses = 0
+}
+
+func (p *Martingale) String() string {
+	return fmt.Sprintf("Martingale from $%d", p.Unit)
+}
+
+// Paroli, the reverse Martingale, doubles the bet after every win and drops
+// back to one unit after a loss or a run of three wins.
+type Paroli struct {
+	Unit int
+	wins int
+}
+
+func (p *Paroli) Bet() int {
+	return p.Unit << p.wins
+}
+
+func (p *Paroli) Record(net int) {
+	switch {
+	case net > 0:
+		p.wins = (p.wins + 1) % 3
+	case net < 0:
+		p.wins = 0
+	}
+}
+
+func (p *Paroli) Reset() {
+	p.wins = 0
+}
+
+func (p *Paroli) String() string {
+	return fmt.Sprintf("Paroli from $%d", p.Unit)
+}
+
+// DAlembert adds a unit after a loss and takes one off after a win, never
+// going below one unit.
+type DAlembert struct {
+	Unit  int
+	extra int
+}
+
+func (p *DAlembert) Bet() int {
+	return p.Unit * (1 + p.extra)
+}
+
+func (p *DAlembert) Record(net int) {
+	switch {
+	case net < 0:
+		p.extra++
+	case net > 0:
+		p.extra = max(p.extra-1, 0)
+	}
+}
+
+func (p *DAlembert) Reset() {
+	p.extra = 0
+}
+
+func (p *DAlembert) String() string {
+	return fmt.Sprintf("D'Alembert from $%d", p.Unit)
+}
+
+// Fibonacci moves one step along 1, 1, 2, 3, 5, … units after a loss and
+// two steps back after a win.
+type Fibonacci struct {
+	Unit int
+	step int
+}
+
+func (p *Fibonacci) Bet() int {
+	a, b := 1, 1
+	for range p.step {
+		a, b = b, a+b
+	}
+	return p.Unit * a
+}
+
+func (p *Fibonacci) Record(net int) {
+	switch {
+	case net < 0:
+		p.step = min(p.step+1, maxSteps)
+	case net > 0:
+		p.step = max(p.step-2, 0)
+	}
+}
+
+func (p *Fibonacci) Reset() {
+	p.step = 0
+}
+
+func (p *Fibonacci) String() string {
+	return fmt.Sprintf("Fibonacci from $%d", p.Unit)
+}
+
+var oneThreeTwoSix = [...]int{1, 3, 2, 6}
+
+// OneThreeTwoSix bets 1, 3, 2 and 6 units on consecutive wins, starting
+// over after a loss or the fourth win.
+type OneThreeTwoSix struct {
+	Unit int
+	step int
+}
+
+func (p *OneThreeTwoSix) Bet() int {
+	return p.Unit * oneThreeTwoSix[p.step]
+}
+
+func (p *OneThreeTwoSix) Record(net int) {
+	switch {
+	case net > 0:
+		p.step = (p.step + 1) % len(oneThreeTwoSix)
+	case net < 0:
+		p.step = 0
+	}
+}
+
+func (p *OneThreeTwoSix) Reset() {
+	p.step = 0
+}
+
+func (p *OneThreeTwoSix) String() string {
+	return fmt.Sprintf("1-3-2-6 from $%d", p.Unit)
+}
+
+// OscarsGrind plays series that each aim to win one unit. The bet stays put
+// after a loss and grows by a unit after a win, but never beyond what would
+// finish the series.
+type OscarsGrind struct {
+	Unit   int
+	extra  int
+	profit int
+}
+
+func (p *OscarsGrind) Bet() int {
+	return p.Unit * (1 + p.extra)
+}
+
+func (p *OscarsGrind) Record(net int) {
+	p.profit += net
+	if p.profit >= p.Unit {
+		p.Reset()
+		return
+	}
+	if net > 0 {
+		// Units still needed to finish the series, rounded up.
+		needed := (p.Unit - p.profit + p.Unit - 1) / p.Unit
+		p.extra = max(min(p.extra+1, needed-1), 0)
+	}
+}
+
+func (p *OscarsGrind) Reset() {
+	p.extra = 0
+	p.profit = 0
+}
+
+func (p *OscarsGrind) String() string {
+	return fmt.Sprintf("Oscar's Grind from $%d", p.Unit)
+}
+
+// NewProgression returns a fresh progression by name with the given unit.
+func NewProgression(name string, unit int) (Progression, error) {
+	unit = max(unit, 1)
+	switch strings.ToLower(name) {
+	case "flat":
+		return &FlatBet{Unit: unit}, nil
+	case "martingale":
+		return &Martingale{Unit: unit}, nil
+	case "paroli", "reverse-martingale":
+		return &Paroli{Unit: unit}, nil
+	case "dalembert":
+		return &DAlembert{Unit: unit}, nil
+	case "fibonacci":
+		return &Fibonacci{Unit: unit}, nil
+	case "1-3-2-6":
+		return &OneThreeTwoSix{Unit: unit}, nil
+	case "oscars-grind":
+		return &OscarsGrind{Unit: unit}, nil
+	default:
+		return nil, fmt.Errorf("unknown progression %q (want %s)", name, strings.Join(ProgressionNames(), ", "))
+	}
+}
+
+// ProgressionNames lists the progressions NewProgression knows.
+func ProgressionNames() []string {
+	return []string{"flat", "martingale", "paroli", "dalembert", "fibonacci", "1-3-2-6", "oscars-grind"}
+}
@@ -0,0 +1,67 @@
+package data
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestProgressions(t *testing.T) {
+	// Results of consecutive rounds: win, lose or push.
+	const w, l, p = 10, -10, 0
+	tests := []struct {
+		name    string
+		results []int
+		bets    []int
+	}{
+		{"flat", []int{l, w, l}, []int{10, 10, 10, 10}},
+		{"martingale", []int{l, l, p, w, l}, []int{10, 20, 40, 40, 10, 20}},
+		{"paroli", []int{w, w, w, w, l}, []int{10, 20, 40, 10, 20, 10}},
+		{"dalembert", []int{l, l, w, w, w}, []int{10, 20, 30, 20, 10, 10}},
+		{"fibonacci", []int{l, l, l, l, w, w}, []int{10, 10, 20, 30, 50, 20, 10}},
+		{"1-3-2-6", []int{w, w, w, w, w, l}, []int{10, 30, 20, 60, 10, 30, 10}},
+		// Down three units, then wins of 1 and 2 units leave the series one
+		// unit short, so the bet is capped at one unit to finish it.
+		{"oscars-grind", []int{l, l, l, w, 20, 10, w}, []int{10, 10, 10, 10, 20, 10, 10, 10}},
+	}
+	for _, test := range tests {
+		progression, err := NewProgression(test.name, 10)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		bets := []int{progression.Bet()}
+		for _, net := range test.results {
+			progression.Record(net)
+			bets = append(bets, progression.Bet())
+		}
+		if !slices.Equal(bets, test.bets) {
+			t.Errorf("%s: bets %v, want %v", test.name, bets, test.bets)
+		}
+		progression.Reset()
+		if got := progression.Bet(); got != 10 {
+			t.Errorf("%s: bet after reset = %d, want one unit", test.name, got)
+		}
+	}
+
+	if _, err := NewProgression("labouchere", 10); err == nil {
+		t.Error("expected an error for an unknown progression")
+	}
+}
+
+func TestCapBet(t *testing.T) {
+	rules := DefaultRules()
+	rules.MinBet = 10
+	rules.MaxBet = 500
+	tests := []struct {
+		bet, bankroll, want int
+	}{
+		{5, 100, 10},
+		{640, 1000, 500},
+		{80, 50, 50},
+		{20, 5, 0},
+	}
+	for _, test := range tests {
+		if got := rules.CapBet(test.bet, test.bankroll); got != test.want {
+			t.Errorf("CapBet(%d, %d) = %d, want %d", test.bet, test.bankroll, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Jackpot is the meter of the progressive side bet. Every progressive
+// wager adds Share of itself to the meter, and a hit pays the whole meter
+// before it starts again from Reset.
+type Jackpot struct {
+	Meter float64 `json:"meter"`
+	Reset float64 `json:"reset"`
+	Share float64 `json:"share"`
+	// Ante is the only amount the progressive takes.
+	Ante int          `json:"ante"`
+	Hits []JackpotHit `json:"hits,omitempty"`
+}
+
+// JackpotHit records a jackpot win with the hands that made it.
+type JackpotHit struct {
+	At     time.Time `json:"at"`
+	Player string    `json:"player"`
+	Amount int       `json:"amount"`
+	Hand   []Card    `json:"hand"`
+	Dealer []Card    `json:"dealer"`
+}
+
+func (h JackpotHit) String() string {
+	return fmt.Sprintf("%s won the $%d jackpot with %s against the dealer's %s",
+		h.Player, h.Amount, cardsString(h.Hand), cardsString(h.Dealer))
+}
+
+func cardsString(cards []Card) string {
+	parts := make([]string, len(cards))
+	for i, card := range cards {
+		parts[i] = card.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func DefaultJackpot() Jackpot {
+	return Jackpot{Meter: 1000, Reset: 1000, Share: 0.15, Ante: 1}
+}
+
+// ProgressiveOutcome judges the player's first two cards and the dealer's
+// upcard and hole card. The jackpot needs suited blackjacks for both.
+func ProgressiveOutcome(first, second, upcard, hole Card) SideOutcome {
+	suitedBlackjack := func(a, b Card) bool {
+		return a.Suit == b.Suit && a.Value()+b.Value() == 21
+	}
+	switch {
+	case suitedBlackjack(first, second) && suitedBlackjack(upcard, hole):
+		return ProgressiveJackpot
+	case suitedBlackjack(first, second):
+		return SuitedBlackjack
+	case first.Value()+second.Value() == 21:
+		return AnyBlackjack
+	default:
+		return SideNoWin
+	}
+}
+
+// Jackpot returns the progressive meter the table shares.
+func (g *Game) Jackpot() *Jackpot {
+	return g.jackpot
+}
+
+// SetJackpot seats the table on a meter kept elsewhere, such as a profile.
+func (g *Game) SetJackpot(jackpot *Jackpot) {
+	g.jackpot = jackpot
+}
+
+// JackpotHits returns the jackpots won in the current or last round.
+func (g *Game) JackpotHits() []JackpotHit {
+	return g.jackpotHits
+}
+
+// settleProgressive pays the progressive bets once the hole card is shown.
+// A player who split no longer has the first two cards, and had no
+// blackjack either.
+func (g *Game) settleProgressive() {
+	dealer := g.dealer.ActiveHand().Cards()
+	for _, player := range g.players {
+		for i := range player.sideBets {
+			bet := &player.sideBets[i]
+			if bet.Bet != SideProgressive {
+				continue
+			}
+			hand := player.Hands()[0]
+			outcome := SideNoWin
+			if !hand.IsSplit() {
+				cards := hand.Cards()
+				outcome = ProgressiveOutcome(cards[0], cards[1], dealer[0], dealer[1])
+			}
+			if outcome != ProgressiveJackpot {
+				g.settleSideBet(player, bet, outcome)
+				continue
+			}
+			amount := int(g.jackpot.Meter)
+			bet.Outcome, bet.Settled, bet.Net = outcome, true, amount
+			player.bankroll += bet.Amount + amount
+			hit := JackpotHit{
+				At:     time.Now(),
+				Player: player.Name(),
+				Amount: amount,
+				Hand:   append([]Card(nil), hand.Cards()...),
+				Dealer: append([]Card(nil), dealer[:2]...),
+			}
+			g.jackpotHits = append(g.jackpotHits, hit)
+			g.jackpot.Hits = append(g.jackpot.Hits, hit)
+			g.jackpot.Meter = g.jackpot.Reset
+		}
+	}
+}
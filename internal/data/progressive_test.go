@@ -0,0 +1,90 @@
+package data
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestProgressiveOutcome(t *testing.T) {
+	tests := []struct {
+		cards [4]Card
+		want  SideOutcome
+	}{
+		{[4]Card{{Spades, Ace}, {Spades, King}, {Hearts, Jack}, {Hearts, Ace}}, ProgressiveJackpot},
+		{[4]Card{{Spades, Ace}, {Spades, King}, {Hearts, Jack}, {Clubs, Ace}}, SuitedBlackjack},
+		{[4]Card{{Spades, Ace}, {Clubs, Ten}, {Hearts, Jack}, {Hearts, Ace}}, AnyBlackjack},
+		{[4]Card{{Spades, Ace}, {Spades, Nine}, {Hearts, Jack}, {Hearts, Ace}}, SideNoWin},
+	}
+	for _, tt := range tests {
+		if got := ProgressiveOutcome(tt.cards[0], tt.cards[1], tt.cards[2], tt.cards[3]); got != tt.want {
+			t.Errorf("%v: expected %v, got %v", tt.cards, tt.want, got)
+		}
+	}
+}
+
+func TestGamePaysJackpot(t *testing.T) {
+	game, err := NewGameWithRules(DefaultRules(), []PlayerConfig{{Name: "Alice", Bankroll: 100}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.SetJackpot(&Jackpot{Meter: 500, Reset: 200, Share: 0.5, Ante: 2})
+	if err := game.StartRoundWithSideBets(map[string]int{"Alice": 10},
+		map[string]SideBets{"Alice": {SideProgressive: 5}}); !errors.Is(err, ErrBetOutOfRange) {
+		t.Fatalf("expected the progressive to refuse anything but the ante, got %v", err)
+	}
+
+	game.deck.cards = []Card{
+		{Suit: Spades, Rank: Ace},  // player card 1
+		{Suit: Hearts, Rank: Ace},  // dealer upcard
+		{Suit: Spades, Rank: King}, // player card 2
+		{Suit: Hearts, Rank: Ten},  // hole card
+	}
+	err = game.StartRoundWithSideBets(map[string]int{"Alice": 10},
+		map[string]SideBets{"Alice": {SideProgressive: 2}})
+	if err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	if meter := game.Jackpot().Meter; meter != 501 {
+		t.Fatalf("expected the wager to feed the meter to 501, got %v", meter)
+	}
+	if err := game.DealInitialCards(); err != nil {
+		t.Fatalf("unexpected deal initial cards error: %v", err)
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected the dealer's blackjack to end the round")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer error: %v", err)
+	}
+	player := game.Players()[0]
+	want := SideBetResult{Bet: SideProgressive, Amount: 2, Outcome: ProgressiveJackpot, Net: 501, Settled: true}
+	if got := player.SideBets()[0]; got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if player.Bankroll() != 88+503 {
+		t.Fatalf("expected the jackpot paid, got bankroll %d", player.Bankroll())
+	}
+	if game.Jackpot().Meter != 200 || len(game.JackpotHits()) != 1 || len(game.Jackpot().Hits) != 1 {
+		t.Fatalf("expected the meter reset and the hit recorded, got %+v", game.Jackpot())
+	}
+	if hit := game.JackpotHits()[0].String(); hit != "Alice won the $501 jackpot with A♠ K♠ against the dealer's A♥ 10♥" {
+		t.Fatalf("unexpected hit description %q", hit)
+	}
+}
+
+func TestProfileRoundTrip(t *testing.T) {
+	profile := NewProfile()
+	profile.Jackpot.Meter = 1234.5
+	var buf bytes.Buffer
+	if err := profile.Save(&buf); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	loaded, err := LoadProfile(&buf)
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if loaded.Jackpot.Meter != 1234.5 || loaded.Jackpot.Ante != 1 {
+		t.Fatalf("unexpected loaded profile %+v", loaded)
+	}
+}
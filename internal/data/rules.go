@@ -0,0 +1,183 @@
+package data
+
+import "fmt"
+
+// Rules describes the table conditions a game is dealt under.
+type Rules struct {
+	Variant          Variant
+	Decks            int
+	DealerHitsSoft17 bool
+	DoubleAfterSplit bool
+	// HitSplitAces lets split aces draw, double and resplit like any other
+	// pair. Without it each split ace takes one card and stands.
+	HitSplitAces bool
+	Surrender    bool
+	// NoHoleCard deals the dealer's second card only after the players
+	// finish, so a dealer blackjack also takes doubles and splits unless
+	// OriginalBetsOnly limits the loss to the bets placed before the deal.
+	NoHoleCard       bool
+	OriginalBetsOnly bool
+	// Charlie is the number of cards that win a hand outright without
+	// busting, or zero for no Charlie.
+	Charlie       int
+	MaxSplitHands int
+	MinBet        int
+	MaxBet        int
+	Penetration   float64
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		Decks:            6,
+		DealerHitsSoft17: true,
+		DoubleAfterSplit: true,
+		Surrender:        false,
+		MaxSplitHands:    4,
+		MinBet:           5,
+		MaxBet:           1000,
+		Penetration:      0.75,
+	}
+}
+
+func (r Rules) Validate() error {
+	if r.Decks <= 0 {
+		return fmt.Errorf("number of decks must be positive")
+	}
+	if r.MaxSplitHands < 1 {
+		return fmt.Errorf("max split hands must be at least one")
+	}
+	if r.MinBet < 0 || (r.MaxBet > 0 && r.MaxBet < r.MinBet) {
+		return fmt.Errorf("table limits must satisfy 0 <= min <= max")
+	}
+	if r.Penetration <= 0 || r.Penetration > 1 {
+		return fmt.Errorf("penetration must be between 0 and 1")
+	}
+	if r.NoHoleCard && r.HoleCardExposed() {
+		return fmt.Errorf("%s deals the hole card face up", r.Variant)
+	}
+	if r.Surrender && r.Variant == Pontoon {
+		return fmt.Errorf("%s has no surrender", r.Variant)
+	}
+	if r.Charlie < 0 || r.Charlie > 0 && r.Charlie < 5 {
+		return fmt.Errorf("a Charlie needs at least five cards")
+	}
+	if r.Variant == Pontoon && r.Charlie != 0 && r.Charlie != 5 {
+		return fmt.Errorf("a %s trick is five cards", r.Variant)
+	}
+	return nil
+}
+
+// CheckBet reports whether amount is inside the table limits. A zero limit
+// is treated as no limit.
+func (r Rules) CheckBet(amount int) error {
+	if r.MinBet > 0 && amount < r.MinBet {
+		return fmt.Errorf("%w: table minimum is $%d", ErrBetOutOfRange, r.MinBet)
+	}
+	if r.MaxBet > 0 && amount > r.MaxBet {
+		return fmt.Errorf("%w: table maximum is $%d", ErrBetOutOfRange, r.MaxBet)
+	}
+	return nil
+}
+
+// CapBet clamps bet to the table limits and bankroll. It returns zero when
+// the bankroll cannot cover the table minimum.
+func (r Rules) CapBet(bet, bankroll int) int {
+	if r.MaxBet > 0 {
+		bet = min(bet, r.MaxBet)
+	}
+	bet = min(bet, bankroll)
+	bet = max(bet, r.MinBet, 1)
+	if bet > bankroll {
+		return 0
+	}
+	return bet
+}
+
+// EstimatedHouseEdge approximates the basic strategy house edge from the
+// commonly published value of each rule, starting from a single deck S17
+// game with no doubling after splits and no surrender. ok is false when no
+// published figure fits the rules.
+func (r Rules) EstimatedHouseEdge() (edge float64, ok bool) {
+	// Variants are published for six decks with doubling after splits,
+	// and Spanish 21 with late surrender. Published Pontoon edges assume
+	// rules this table does not deal.
+	switch r.Variant {
+	case Spanish21:
+		if r.DealerHitsSoft17 {
+			return 0.0076, true
+		}
+		return 0.0040, true
+	case BlackjackSwitch:
+		return 0.0058, true
+	case DoubleExposure:
+		return 0.0069, true
+	case FreeBet:
+		return 0.0103, true
+	case Pontoon:
+		return 0, false
+	}
+	switch {
+	case r.Decks == 1:
+	case r.Decks == 2:
+		edge += 0.0035
+	case r.Decks <= 4:
+		edge += 0.0048
+	case r.Decks <= 6:
+		edge += 0.0054
+	default:
+		edge += 0.0057
+	}
+	if r.DealerHitsSoft17 {
+		edge += 0.0020
+	}
+	if r.DoubleAfterSplit {
+		edge -= 0.0014
+	}
+	if r.HitSplitAces {
+		edge -= 0.0027
+	}
+	if r.Surrender {
+		edge -= 0.0008
+	}
+	if r.NoHoleCard && !r.OriginalBetsOnly {
+		edge += 0.0011
+	}
+	// A Charlie of seven or more cards is worth under 0.01%.
+	switch r.Charlie {
+	case 5:
+		edge -= 0.0142
+	case 6:
+		edge -= 0.0014
+	}
+	return edge, true
+}
+
+func (r Rules) String() string {
+	soft17 := "S17"
+	if r.DealerHitsSoft17 {
+		soft17 = "H17"
+	}
+	s := fmt.Sprintf("%d decks, %s", r.Decks, soft17)
+	if r.Variant != Standard {
+		s = r.Variant.String() + ", " + s
+	}
+	if r.DoubleAfterSplit {
+		s += ", DAS"
+	}
+	if r.HitSplitAces {
+		s += ", HSA"
+	}
+	if r.Surrender {
+		s += ", LS"
+	}
+	if r.Charlie > 0 {
+		s += fmt.Sprintf(", %d-card Charlie", r.Charlie)
+	}
+	if r.NoHoleCard {
+		s += ", ENHC"
+		if r.OriginalBetsOnly {
+			s += " OBO"
+		}
+	}
+	return s
+}
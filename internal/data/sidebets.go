@@ -0,0 +1,304 @@
+package data
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SideBet names a side-bet spot next to the main bet.
+type SideBet int
+
+const (
+	// SidePerfectPairs is judged on the player's first two cards.
+	SidePerfectPairs SideBet = iota
+	// Side21Plus3 is judged on the player's first two cards and the dealer
+	// upcard as a three-card poker hand.
+	Side21Plus3
+	// SideDealerBust pays when the dealer busts, more the more cards it
+	// took. It is settled once the dealer has played.
+	SideDealerBust
+	// SideProgressive is judged on the player's blackjack and pays the
+	// jackpot meter when the dealer has one too, both suited.
+	SideProgressive
+)
+
+var sideBetNames = []string{"Perfect Pairs", "21+3", "Dealer Bust", "Progressive"}
+
+// SideBetSpots lists every side bet in table order.
+func SideBetSpots() []SideBet {
+	return []SideBet{SidePerfectPairs, Side21Plus3, SideDealerBust, SideProgressive}
+}
+
+func (s SideBet) String() string {
+	if s < 0 || int(s) >= len(sideBetNames) {
+		return fmt.Sprintf("SideBet(%d)", int(s))
+	}
+	return sideBetNames[s]
+}
+
+// SideBets holds a seat's side-bet stakes by spot.
+type SideBets map[SideBet]int
+
+// SideOutcome is the hand a side bet made.
+type SideOutcome int
+
+const (
+	SideNoWin SideOutcome = iota
+	MixedPair
+	ColoredPair
+	PerfectPair
+	Flush
+	Straight
+	ThreeOfAKind
+	StraightFlush
+	SuitedTrips
+	BustThreeCards
+	BustFourCards
+	BustFiveCards
+	BustSixCards
+	BustSevenCards
+	// BustEightCards covers a bust with eight or more cards.
+	BustEightCards
+	AnyBlackjack
+	SuitedBlackjack
+	// ProgressiveJackpot pays the meter rather than from a paytable.
+	ProgressiveJackpot
+)
+
+var sideOutcomeNames = []string{
+	"no win", "mixed pair", "colored pair", "perfect pair",
+	"flush", "straight", "three of a kind", "straight flush", "suited trips",
+	"bust with 3 cards", "bust with 4 cards", "bust with 5 cards",
+	"bust with 6 cards", "bust with 7 cards", "bust with 8+ cards",
+	"blackjack", "suited blackjack", "jackpot",
+}
+
+func (o SideOutcome) String() string {
+	if o < 0 || int(o) >= len(sideOutcomeNames) {
+		return fmt.Sprintf("SideOutcome(%d)", int(o))
+	}
+	return sideOutcomeNames[o]
+}
+
+// Paytable maps each winning outcome of a side bet to what it pays to one.
+// Outcomes missing from the table lose.
+type Paytable map[SideOutcome]int
+
+// DefaultPaytable returns the common paytable for bet. The dealer bust
+// table is the one Bust It is usually dealt with.
+func DefaultPaytable(bet SideBet) Paytable {
+	switch bet {
+	case SidePerfectPairs:
+		return Paytable{MixedPair: 6, ColoredPair: 12, PerfectPair: 25}
+	case Side21Plus3:
+		return Paytable{Flush: 5, Straight: 10, ThreeOfAKind: 30, StraightFlush: 40, SuitedTrips: 100}
+	case SideDealerBust:
+		return Paytable{BustThreeCards: 1, BustFourCards: 2, BustFiveCards: 9, BustSixCards: 50, BustSevenCards: 100, BustEightCards: 250}
+	case SideProgressive:
+		return Paytable{AnyBlackjack: 5, SuitedBlackjack: 40}
+	default:
+		return Paytable{}
+	}
+}
+
+// Outcomes lists the hands bet can win with, lowest first.
+func (s SideBet) Outcomes() []SideOutcome {
+	switch s {
+	case SidePerfectPairs:
+		return []SideOutcome{MixedPair, ColoredPair, PerfectPair}
+	case Side21Plus3:
+		return []SideOutcome{Flush, Straight, ThreeOfAKind, StraightFlush, SuitedTrips}
+	case SideDealerBust:
+		return []SideOutcome{BustThreeCards, BustFourCards, BustFiveCards, BustSixCards, BustSevenCards, BustEightCards}
+	case SideProgressive:
+		// The jackpot pays the meter.
+		return []SideOutcome{AnyBlackjack, SuitedBlackjack}
+	default:
+		return nil
+	}
+}
+
+// ParsePaytable reads what each of bet's outcomes pays, comma separated in
+// the order of Outcomes, for example "6,12,25" for Perfect Pairs.
+func ParsePaytable(bet SideBet, s string) (Paytable, error) {
+	outcomes := bet.Outcomes()
+	parts := strings.Split(s, ",")
+	if len(parts) != len(outcomes) {
+		return nil, fmt.Errorf("%s paytable needs %d pays, got %d", bet, len(outcomes), len(parts))
+	}
+	table := Paytable{}
+	for i, part := range parts {
+		odds, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || odds < 0 {
+			return nil, fmt.Errorf("%s pay %q for %s must be a whole number of at least zero", bet, part, outcomes[i])
+		}
+		table[outcomes[i]] = odds
+	}
+	return table, nil
+}
+
+// SideBetResult is the settlement of one side bet. Net is the amount won,
+// or minus the stake when the bet lost or is not Settled yet.
+type SideBetResult struct {
+	Bet     SideBet
+	Amount  int
+	Outcome SideOutcome
+	Net     int
+	Settled bool
+}
+
+func (c Card) red() bool {
+	return c.Suit == Hearts || c.Suit == Diamonds
+}
+
+// PerfectPairsOutcome judges two cards of the same rank: a perfect pair
+// shares the suit, a colored pair the color and a mixed pair neither.
+func PerfectPairsOutcome(first, second Card) SideOutcome {
+	switch {
+	case first.Rank != second.Rank:
+		return SideNoWin
+	case first.Suit == second.Suit:
+		return PerfectPair
+	case first.red() == second.red():
+		return ColoredPair
+	default:
+		return MixedPair
+	}
+}
+
+// TwentyOnePlusThreeOutcome judges three cards as a poker hand. Aces count
+// high or low in a straight.
+func TwentyOnePlusThreeOutcome(first, second, upcard Card) SideOutcome {
+	flush := first.Suit == second.Suit && second.Suit == upcard.Suit
+	ranks := []int{int(first.Rank), int(second.Rank), int(upcard.Rank)}
+	slices.Sort(ranks)
+	trips := ranks[0] == ranks[2]
+	straight := ranks[0]+1 == ranks[1] && ranks[1]+1 == ranks[2] ||
+		ranks[0] == int(Ace) && ranks[1] == int(Queen) && ranks[2] == int(King)
+	switch {
+	case trips && flush:
+		return SuitedTrips
+	case straight && flush:
+		return StraightFlush
+	case trips:
+		return ThreeOfAKind
+	case straight:
+		return Straight
+	case flush:
+		return Flush
+	default:
+		return SideNoWin
+	}
+}
+
+// DealerBustOutcome judges a dealer hand of cards cards.
+func DealerBustOutcome(cards int, busted bool) SideOutcome {
+	if !busted || cards < 3 {
+		return SideNoWin
+	}
+	return BustThreeCards + SideOutcome(min(cards, 8)-3)
+}
+
+// SetPaytable replaces the paytable bet settles with.
+func (g *Game) SetPaytable(bet SideBet, table Paytable) {
+	g.paytables[bet] = table
+}
+
+// Paytable returns the paytable bet settles with.
+func (g *Game) Paytable(bet SideBet) Paytable {
+	return g.paytables[bet]
+}
+
+// StartRoundWithSideBets starts a round like StartRound and also takes each
+// seat's side bets out of its bankroll. Side bets must fit the table
+// maximum but have no minimum. They are checked before the round starts,
+// so a bad side bet leaves the table still taking bets.
+func (g *Game) StartRoundWithSideBets(bets map[string]int, sideBets map[string]SideBets) error {
+	if g.state != StateBetting {
+		return ErrInvalidState
+	}
+	for _, player := range g.players {
+		total := bets[player.Name()] * g.rules.Boxes()
+		for bet, amount := range sideBets[player.Name()] {
+			if amount < 0 {
+				return fmt.Errorf("player %s %s bet failed: %w", player.Name(), bet, ErrInvalidBet)
+			}
+			if g.rules.MaxBet > 0 && amount > g.rules.MaxBet {
+				return fmt.Errorf("player %s %s bet failed: %w: table maximum is $%d", player.Name(), bet, ErrBetOutOfRange, g.rules.MaxBet)
+			}
+			if bet == SideProgressive && amount > 0 && amount != g.jackpot.Ante {
+				return fmt.Errorf("player %s %s bet failed: %w: the progressive takes $%d", player.Name(), bet, ErrBetOutOfRange, g.jackpot.Ante)
+			}
+			total += amount
+		}
+		if total > player.Bankroll() {
+			return fmt.Errorf("player %s side bets failed: %w", player.Name(), ErrInsufficientBankroll)
+		}
+	}
+	if err := g.StartRound(bets); err != nil {
+		return err
+	}
+	for _, player := range g.players {
+		for _, bet := range SideBetSpots() {
+			amount := sideBets[player.Name()][bet]
+			if amount == 0 || amount > player.bankroll {
+				continue
+			}
+			player.bankroll -= amount
+			player.sideBets = append(player.sideBets, SideBetResult{Bet: bet, Amount: amount, Net: -amount})
+			if bet == SideProgressive {
+				g.jackpot.Meter += float64(amount) * g.jackpot.Share
+			}
+		}
+	}
+	return nil
+}
+
+// settleSideBets pays the side bets on the cards just dealt, as the
+// dealer does before anyone acts.
+func (g *Game) settleSideBets() {
+	upcard := g.dealer.ActiveHand().Cards()[0]
+	for _, player := range g.players {
+		cards := player.ActiveHand().Cards()
+		for i := range player.sideBets {
+			bet := &player.sideBets[i]
+			switch bet.Bet {
+			case SidePerfectPairs:
+				g.settleSideBet(player, bet, PerfectPairsOutcome(cards[0], cards[1]))
+			case Side21Plus3:
+				g.settleSideBet(player, bet, TwentyOnePlusThreeOutcome(cards[0], cards[1], upcard))
+			}
+		}
+	}
+}
+
+// settleDealerBust pays the dealer bust bets once the dealer has played.
+func (g *Game) settleDealerBust() {
+	hand := g.dealer.ActiveHand()
+	outcome := DealerBustOutcome(len(hand.Cards()), hand.IsBusted())
+	for _, player := range g.players {
+		for i := range player.sideBets {
+			if bet := &player.sideBets[i]; bet.Bet == SideDealerBust {
+				g.settleSideBet(player, bet, outcome)
+			}
+		}
+	}
+}
+
+func (g *Game) settleSideBet(player *Player, bet *SideBetResult, outcome SideOutcome) {
+	bet.Outcome = outcome
+	bet.Settled = true
+	if odds := g.paytables[bet.Bet][outcome]; odds > 0 {
+		bet.Net = bet.Amount * odds
+		player.bankroll += bet.Amount * (odds + 1)
+	}
+}
+
+// SideBets returns the player's side bets this round. Most are settled
+// once the cards are dealt, the dealer bust and progressive once the dealer
+// has played.
+func (p *Player) SideBets() []SideBetResult {
+	return p.sideBets
+}
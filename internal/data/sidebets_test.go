@@ -0,0 +1,187 @@
+package data
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPerfectPairsOutcome(t *testing.T) {
+	tests := []struct {
+		first, second Card
+		want          SideOutcome
+	}{
+		{Card{Spades, Eight}, Card{Spades, Eight}, PerfectPair},
+		{Card{Hearts, Eight}, Card{Diamonds, Eight}, ColoredPair},
+		{Card{Clubs, King}, Card{Hearts, King}, MixedPair},
+		{Card{Clubs, King}, Card{Clubs, Queen}, SideNoWin},
+	}
+	for _, tt := range tests {
+		if got := PerfectPairsOutcome(tt.first, tt.second); got != tt.want {
+			t.Errorf("%v %v: expected %v, got %v", tt.first, tt.second, tt.want, got)
+		}
+	}
+}
+
+func TestTwentyOnePlusThreeOutcome(t *testing.T) {
+	tests := []struct {
+		cards [3]Card
+		want  SideOutcome
+	}{
+		{[3]Card{{Hearts, Seven}, {Hearts, Seven}, {Hearts, Seven}}, SuitedTrips},
+		{[3]Card{{Clubs, Nine}, {Clubs, Jack}, {Clubs, Ten}}, StraightFlush},
+		{[3]Card{{Clubs, Seven}, {Hearts, Seven}, {Spades, Seven}}, ThreeOfAKind},
+		{[3]Card{{Clubs, Ace}, {Hearts, Two}, {Spades, Three}}, Straight},
+		{[3]Card{{Clubs, Queen}, {Hearts, Ace}, {Spades, King}}, Straight},
+		{[3]Card{{Clubs, King}, {Hearts, Ace}, {Spades, Two}}, SideNoWin},
+		{[3]Card{{Diamonds, Two}, {Diamonds, Nine}, {Diamonds, King}}, Flush},
+		{[3]Card{{Diamonds, Two}, {Spades, Nine}, {Diamonds, King}}, SideNoWin},
+	}
+	for _, tt := range tests {
+		if got := TwentyOnePlusThreeOutcome(tt.cards[0], tt.cards[1], tt.cards[2]); got != tt.want {
+			t.Errorf("%v: expected %v, got %v", tt.cards, tt.want, got)
+		}
+	}
+}
+
+func TestGameSettlesSideBets(t *testing.T) {
+	game, err := NewGameWithRules(DefaultRules(), []PlayerConfig{{Name: "Alice", Bankroll: 100}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.SetPaytable(Side21Plus3, Paytable{Flush: 3})
+	game.deck.cards = []Card{
+		{Suit: Hearts, Rank: Eight}, // player card 1
+		{Suit: Hearts, Rank: Two},   // dealer upcard
+		{Suit: Hearts, Rank: Eight}, // player card 2
+		{Suit: Clubs, Rank: Ten},
+	}
+	err = game.StartRoundWithSideBets(map[string]int{"Alice": 10},
+		map[string]SideBets{"Alice": {SidePerfectPairs: 5, Side21Plus3: 5}})
+	if err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	player := game.Players()[0]
+	if player.Bankroll() != 80 {
+		t.Fatalf("expected side bets to come out of the bankroll, got %d", player.Bankroll())
+	}
+	if err := game.DealInitialCards(); err != nil {
+		t.Fatalf("unexpected deal initial cards error: %v", err)
+	}
+	want := []SideBetResult{
+		{Bet: SidePerfectPairs, Amount: 5, Outcome: PerfectPair, Net: 125, Settled: true},
+		{Bet: Side21Plus3, Amount: 5, Outcome: Flush, Net: 15, Settled: true},
+	}
+	got := player.SideBets()
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if player.Bankroll() != 80+130+20 {
+		t.Fatalf("expected side bets paid at the deal, got bankroll %d", player.Bankroll())
+	}
+
+	if err := game.Stand(player); err != nil {
+		t.Fatalf("unexpected stand error: %v", err)
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected game to be ready for dealer")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer error: %v", err)
+	}
+	results, err := game.SettleRound()
+	if err != nil {
+		t.Fatalf("unexpected settle error: %v", err)
+	}
+	if len(results[0].SideBets) != 2 {
+		t.Fatalf("expected the round result to report both side bets, got %v", results[0].SideBets)
+	}
+}
+
+func TestSideBetLimits(t *testing.T) {
+	game, err := NewGameWithRules(DefaultRules(), []PlayerConfig{{Name: "Alice", Bankroll: 20}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	err = game.StartRoundWithSideBets(map[string]int{"Alice": 10},
+		map[string]SideBets{"Alice": {SidePerfectPairs: 15}})
+	if !errors.Is(err, ErrInsufficientBankroll) {
+		t.Fatalf("expected insufficient bankroll error, got %v", err)
+	}
+	if game.State() != StateBetting || game.Players()[0].Bankroll() != 20 {
+		t.Fatal("expected a refused side bet to leave the table taking bets")
+	}
+}
+
+func TestParsePaytable(t *testing.T) {
+	table, err := ParsePaytable(SidePerfectPairs, "5, 10,30")
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if table[MixedPair] != 5 || table[ColoredPair] != 10 || table[PerfectPair] != 30 {
+		t.Fatalf("unexpected paytable %v", table)
+	}
+	if _, err := ParsePaytable(Side21Plus3, "5,10,30"); err == nil {
+		t.Fatal("expected a short 21+3 paytable to be refused")
+	}
+}
+
+func TestDealerBustOutcome(t *testing.T) {
+	tests := []struct {
+		cards  int
+		busted bool
+		want   SideOutcome
+	}{
+		{3, true, BustThreeCards},
+		{5, true, BustFiveCards},
+		{9, true, BustEightCards},
+		{4, false, SideNoWin},
+	}
+	for _, tt := range tests {
+		if got := DealerBustOutcome(tt.cards, tt.busted); got != tt.want {
+			t.Errorf("%d cards, busted %v: expected %v, got %v", tt.cards, tt.busted, tt.want, got)
+		}
+	}
+}
+
+func TestGameSettlesDealerBustAfterDealerPlays(t *testing.T) {
+	game, err := NewGameWithRules(DefaultRules(), []PlayerConfig{{Name: "Alice", Bankroll: 100}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.deck.cards = []Card{
+		{Suit: Spades, Rank: Ten},  // player card 1
+		{Suit: Clubs, Rank: Six},   // dealer upcard
+		{Suit: Hearts, Rank: Nine}, // player card 2
+		{Suit: Clubs, Rank: Two},   // hole card
+		{Suit: Clubs, Rank: Five},  // dealer to 13
+		{Suit: Clubs, Rank: King},  // dealer busts with 4 cards
+	}
+	err = game.StartRoundWithSideBets(map[string]int{"Alice": 10},
+		map[string]SideBets{"Alice": {SideDealerBust: 5}})
+	if err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	if err := game.DealInitialCards(); err != nil {
+		t.Fatalf("unexpected deal initial cards error: %v", err)
+	}
+	player := game.Players()[0]
+	if player.SideBets()[0].Settled {
+		t.Fatal("expected the dealer bust bet to wait for the dealer")
+	}
+	if err := game.Stand(player); err != nil {
+		t.Fatalf("unexpected stand error: %v", err)
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected game to be ready for dealer")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer error: %v", err)
+	}
+	want := SideBetResult{Bet: SideDealerBust, Amount: 5, Outcome: BustFourCards, Net: 10, Settled: true}
+	if got := player.SideBets()[0]; got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if player.Bankroll() != 85+15 {
+		t.Fatalf("expected the bust bet paid 2 to 1, got bankroll %d", player.Bankroll())
+	}
+}
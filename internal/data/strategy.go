@@ -0,0 +1,474 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Action is a playing decision for a single hand.
+type Action int
+
+const (
+	ActionHit Action = iota
+	ActionStand
+	ActionDouble
+	ActionSplit
+	ActionSurrender
+)
+
+var actionNames = []string{"hit", "stand", "double", "split", "surrender"}
+
+func (a Action) String() string {
+	if a < 0 || int(a) >= len(actionNames) {
+		return fmt.Sprintf("Action(%d)", int(a))
+	}
+	return actionNames[a]
+}
+
+func ParseAction(s string) (Action, error) {
+	for i, name := range actionNames {
+		if strings.EqualFold(s, name) {
+			return Action(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown action %q", s)
+}
+
+func (a Action) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Action) UnmarshalText(text []byte) error {
+	parsed, err := ParseAction(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Strategy chart codes, one per dealer upcard from 2 through ace:
+//
+//	H hit, S stand, D double else hit, d double else stand, P split,
+//	p split if doubling after a split is allowed else hit,
+//	R surrender else hit, r surrender else stand, Q surrender else split.
+type chartSet struct {
+	hard, soft, pair map[int]string
+	// Cells that change when the dealer hits soft 17, keyed the same way
+	// as the charts with the upcard as the second element.
+	hardH17, softH17, pairH17 map[[2]int]byte
+}
+
+var standardCharts = chartSet{
+	hard: map[int]string{
+		8:  "HHHHHHHHHH",
+		9:  "HDDDDHHHHH",
+		10: "DDDDDDDDHH",
+		11: "DDDDDDDDDH",
+		12: "HHSSSHHHHH",
+		13: "SSSSSHHHHH",
+		14: "SSSSSHHHHH",
+		15: "SSSSSHHHRH",
+		16: "SSSSSHHRRR",
+		17: "SSSSSSSSSS",
+	},
+	soft: map[int]string{
+		12: "HHHHHHHHHH",
+		13: "HHHDDHHHHH",
+		14: "HHHDDHHHHH",
+		15: "HHDDDHHHHH",
+		16: "HHDDDHHHHH",
+		17: "HDDDDHHHHH",
+		18: "SddddSSHHH",
+		19: "SSSSSSSSSS",
+		20: "SSSSSSSSSS",
+		21: "SSSSSSSSSS",
+	},
+	pair: map[int]string{
+		2:  "ppPPPPHHHH",
+		3:  "ppPPPPHHHH",
+		4:  "HHHppHHHHH",
+		5:  "DDDDDDDDHH",
+		6:  "pPPPPHHHHH",
+		7:  "PPPPPPHHHH",
+		8:  "PPPPPPPPPP",
+		9:  "PPPPPSPPSS",
+		10: "SSSSSSSSSS",
+		11: "PPPPPPPPPP",
+	},
+	hardH17: map[[2]int]byte{{11, 11}: 'D', {15, 11}: 'R', {17, 11}: 'r'},
+	softH17: map[[2]int]byte{{18, 2}: 'd', {19, 6}: 'd'},
+	pairH17: map[[2]int]byte{{8, 11}: 'Q'},
+}
+
+// spanishCharts play a shoe without tens, where doubling is worth less and
+// a 21 never loses.
+var spanishCharts = chartSet{
+	hard: map[int]string{
+		8:  "HHHHHHHHHH",
+		9:  "HHHHDHHHHH",
+		10: "DDDDDDHHHH",
+		11: "DDDDDDDDDD",
+		12: "HHSSSHHHHH",
+		13: "HSSSSHHHHH",
+		14: "SSSSSHHHHH",
+		15: "SSSSSHHHHH",
+		16: "SSSSSHHHHR",
+		17: "SSSSSSSSSS",
+	},
+	soft: map[int]string{
+		12: "HHHHHHHHHH",
+		13: "HHHHDHHHHH",
+		14: "HHHDDHHHHH",
+		15: "HHHDDHHHHH",
+		16: "HHHDDHHHHH",
+		17: "HHDDDHHHHH",
+		18: "SSdddSSHHH",
+		19: "SSSSSSSSSS",
+		20: "SSSSSSSSSS",
+		21: "SSSSSSSSSS",
+	},
+	pair: map[int]string{
+		2:  "HPPPPPHHHH",
+		3:  "HHPPPPPHHH",
+		4:  "HHHHHHHHHH",
+		5:  "DDDDDDHHHH",
+		6:  "HHPPPHHHHH",
+		7:  "PPPPPPHHHH",
+		8:  "PPPPPPPPPP",
+		9:  "SPPPPSPPSS",
+		10: "SSSSSSSSSS",
+		11: "PPPPPPPPPP",
+	},
+	hardH17: map[[2]int]byte{{17, 11}: 'r'},
+	pairH17: map[[2]int]byte{{8, 11}: 'Q'},
+}
+
+// freeBetCharts take every free double and split, since the house stakes
+// them, and give up surrendering, which a dealer 22 makes less attractive.
+var freeBetCharts = chartSet{
+	hard: map[int]string{
+		8:  "HHHHHHHHHH",
+		9:  "DDDDDDDDDD",
+		10: "DDDDDDDDDD",
+		11: "DDDDDDDDDD",
+		12: "HHSSSHHHHH",
+		13: "SSSSSHHHHH",
+		14: "SSSSSHHHHH",
+		15: "SSSSSHHHHH",
+		16: "SSSSSHHHHH",
+		17: "SSSSSSSSSS",
+	},
+	soft: standardCharts.soft,
+	pair: map[int]string{
+		2:  "PPPPPPPPPP",
+		3:  "PPPPPPPPPP",
+		4:  "PPPPPPPPPP",
+		5:  "DDDDDDDDDD",
+		6:  "PPPPPPPPPP",
+		7:  "PPPPPPPPPP",
+		8:  "PPPPPPPPPP",
+		9:  "PPPPPPPPPP",
+		10: "SSSSSSSSSS",
+		11: "PPPPPPPPPP",
+	},
+	softH17: standardCharts.softH17,
+}
+
+// Situation is the part of a hand that playing decisions depend on, so
+// callers that track hands without *Hand can still ask for advice.
+type Situation struct {
+	Total int
+	Soft  bool
+	// Pair is the value of each card when the hand is a splittable pair.
+	Pair  int
+	Cards int
+	Split bool
+	// Doubled marks a doubled hand still open for a rescue.
+	Doubled bool
+}
+
+func (h *Hand) Situation() Situation {
+	s := Situation{
+		Total:   h.Value(),
+		Soft:    h.IsSoft(),
+		Cards:   len(h.cards),
+		Split:   h.split,
+		Doubled: h.doubled && !h.stood,
+	}
+	if h.CanSplit() {
+		s.Pair = h.cards[0].Value()
+	}
+	return s
+}
+
+// Advisor recommends plays for the current rules, optionally adjusting basic
+// strategy with count-based index plays.
+type Advisor struct {
+	Rules      Rules
+	Deviations *IndexTable
+}
+
+func NewAdvisor(rules Rules) *Advisor {
+	return &Advisor{Rules: rules}
+}
+
+// Recommend returns the best play for hand against the dealer upcard.
+// canSplit lets callers veto a split the table limits or bankroll forbid.
+func (a *Advisor) Recommend(hand *Hand, upcard Card, canSplit bool, trueCount float64) Action {
+	action, _ := a.RecommendSituation(hand.Situation(), upcard.Value(), canSplit, trueCount)
+	return action
+}
+
+// RecommendWithReason is Recommend that also returns the index play that
+// overrode basic strategy, if any.
+func (a *Advisor) RecommendWithReason(hand *Hand, upcard Card, canSplit bool, trueCount float64) (Action, *IndexPlay) {
+	return a.RecommendSituation(hand.Situation(), upcard.Value(), canSplit, trueCount)
+}
+
+// RecommendAgainst is RecommendWithReason given the dealer's whole hand,
+// so a table that deals the hole card face up is played against both
+// cards.
+func (a *Advisor) RecommendAgainst(hand, dealer *Hand, canSplit bool, trueCount float64) (Action, *IndexPlay) {
+	cards := dealer.Cards()
+	if a.Rules.HoleCardExposed() && len(cards) > 1 {
+		s := hand.Situation()
+		return a.exposed(s, dealer.Value(), dealer.IsSoft(), canSplit && s.Pair > 0), nil
+	}
+	return a.RecommendWithReason(hand, cards[0], canSplit, trueCount)
+}
+
+// recommendView asks for the play on the active hand of view.
+func (a *Advisor) recommendView(view TableView, trueCount float64) (Action, *IndexPlay) {
+	s := view.ActiveHand().Situation()
+	if a.Rules.HoleCardExposed() && view.Hole.Rank != 0 {
+		dealer := &Hand{cards: []Card{view.Upcard, view.Hole}}
+		return a.exposed(s, dealer.Value(), dealer.IsSoft(), view.CanSplit() && s.Pair > 0), nil
+	}
+	return a.RecommendSituation(s, view.Upcard.Value(), view.CanSplit(), trueCount)
+}
+
+// RecommendSituation is the allocation-free core of Recommend. The upcard is
+// given by value, with 11 for an ace.
+func (a *Advisor) RecommendSituation(s Situation, upcard int, canSplit bool, trueCount float64) (Action, *IndexPlay) {
+	if s.Doubled && a.Rules.DoubleRescue() {
+		return a.rescue(s, upcard), nil
+	}
+	canSplit = canSplit && s.Pair > 0
+	basic := a.basic(s, upcard, canSplit)
+	// Index plays need the upcard, which a hidden one cannot give.
+	if a.Deviations == nil || a.Rules.UpcardHidden() {
+		return basic, nil
+	}
+	splitting := basic == ActionSplit
+	plays := a.Deviations.Plays
+	for i := range plays {
+		play := &plays[i]
+		// Where basic strategy surrenders, only a surrender index applies;
+		// the I18 stand on 16 v 10 assumes no surrender.
+		if basic == ActionSurrender && play.Action != ActionSurrender {
+			continue
+		}
+		if play.covers(s, upcard, canSplit, splitting) && play.Triggered(trueCount) && a.available(play.Action, s, canSplit) {
+			return play.Action, play
+		}
+	}
+	return basic, nil
+}
+
+// rescue decides a doubled hand left open: a stiff hand against a strong
+// upcard is worth less than the half of the bet a rescue returns.
+func (a *Advisor) rescue(s Situation, upcard int) Action {
+	if a.Rules.DoubleRescue() && s.Total <= 16 && upcard >= 8 {
+		return ActionSurrender
+	}
+	return ActionStand
+}
+
+// Basic returns the plain basic strategy play, ignoring the count.
+func (a *Advisor) Basic(hand *Hand, upcard Card, canSplit bool) Action {
+	s := hand.Situation()
+	return a.basic(s, upcard.Value(), canSplit && s.Pair > 0)
+}
+
+func (a *Advisor) basic(s Situation, upcard int, canSplit bool) Action {
+	if a.Rules.Variant == Pontoon {
+		return a.pontoon(s, canSplit)
+	}
+	if a.charlieHit(s) {
+		return ActionHit
+	}
+	code := a.chartCode(s, upcard, canSplit)
+	canDouble := a.canDouble(s)
+	canSurrender := a.canSurrender(s)
+	switch code {
+	case 'S':
+		return ActionStand
+	case 'D':
+		if canDouble {
+			return ActionDouble
+		}
+		return ActionHit
+	case 'd':
+		if canDouble {
+			return ActionDouble
+		}
+		return ActionStand
+	case 'P':
+		return ActionSplit
+	case 'R':
+		if canSurrender {
+			return ActionSurrender
+		}
+		return ActionHit
+	case 'r':
+		if canSurrender {
+			return ActionSurrender
+		}
+		return ActionStand
+	case 'Q':
+		if canSurrender {
+			return ActionSurrender
+		}
+		return ActionSplit
+	default:
+		return ActionHit
+	}
+}
+
+func (a *Advisor) charts() *chartSet {
+	switch a.Rules.Variant {
+	case Spanish21:
+		return &spanishCharts
+	case FreeBet:
+		return &freeBetCharts
+	default:
+		return &standardCharts
+	}
+}
+
+func (a *Advisor) chartCode(s Situation, up int, canSplit bool) byte {
+	charts := a.charts()
+	col := up - 2
+	if canSplit {
+		code := charts.pair[s.Pair][col]
+		if a.Rules.DealerHitsSoft17 {
+			if override, ok := charts.pairH17[[2]int{s.Pair, up}]; ok {
+				code = override
+			}
+		}
+		if code == 'p' {
+			if a.Rules.DoubleAfterSplit {
+				return 'P'
+			}
+			code = 'H'
+		}
+		if code == 'P' || code == 'Q' {
+			return code
+		}
+		// Fives and other non-split pairs fall through to the hard or
+		// soft charts, which agree with the pair chart for those cells.
+	}
+
+	if s.Soft && s.Total >= 12 {
+		code := charts.soft[s.Total][col]
+		if a.Rules.DealerHitsSoft17 {
+			if override, ok := charts.softH17[[2]int{s.Total, up}]; ok {
+				code = override
+			}
+		}
+		return code
+	}
+	row := min(max(s.Total, 8), 17)
+	code := charts.hard[row][col]
+	if a.Rules.DealerHitsSoft17 {
+		// Keyed by the real total: row 17 also covers hard 18 and up.
+		if override, ok := charts.hardH17[[2]int{s.Total, up}]; ok {
+			code = override
+		}
+	}
+	return code
+}
+
+// exposed plays against a dealer hand dealt face up, where ties lose. It
+// approximates the published Double Exposure chart: against a dealer who
+// stands the hand draws until it is ahead, against a stiff it doubles and
+// splits freely, and otherwise it reads a hard total as an upcard of that
+// value and a soft total as a ten.
+func (a *Advisor) exposed(s Situation, dealer int, soft, canSplit bool) Action {
+	canDouble := a.canDouble(s)
+	switch {
+	case !DealerShouldHit(dealer, soft, a.Rules.DealerHitsSoft17):
+		if s.Total > dealer {
+			return ActionStand
+		}
+		return ActionHit
+	case !soft && dealer >= 12:
+		switch {
+		case canSplit && s.Pair != 5 && (s.Pair != 10 || dealer >= 13):
+			return ActionSplit
+		case canDouble && (s.Soft && s.Total <= 20 || !s.Soft && s.Total >= 5 && s.Total <= 11):
+			return ActionDouble
+		case s.Soft && s.Total >= 18 || !s.Soft && s.Total >= 12:
+			return ActionStand
+		default:
+			return ActionHit
+		}
+	case soft:
+		return a.basic(s, 10, canSplit)
+	default:
+		return a.basic(s, dealer, canSplit)
+	}
+}
+
+// charlieHit overrides the charts for a hand one card short of a Charlie:
+// a soft hand cannot bust on the card that wins it, and a hard 15 or less
+// wins more often by drawing than by standing.
+func (a *Advisor) charlieHit(s Situation) bool {
+	charlie := a.Rules.CharlieCards()
+	return charlie > 0 && s.Cards == charlie-1 && (s.Soft || s.Total <= 15)
+}
+
+// pontoon plays without a dealer card to go on, so the hand alone decides:
+// split aces and eights, buy on a hard 9 to 11, twist a four-card 17 or less
+// for the trick, and since the dealer wins ties, otherwise stick only on a
+// hard 15 or a soft 19.
+func (a *Advisor) pontoon(s Situation, canSplit bool) Action {
+	switch {
+	case canSplit && (s.Pair == 11 || s.Pair == 8):
+		return ActionSplit
+	case a.canDouble(s) && s.Cards == 2 && !s.Soft && s.Total >= 9 && s.Total <= 11:
+		return ActionDouble
+	case s.Cards == a.Rules.CharlieCards()-1 && s.Total <= 17:
+		return ActionHit
+	case s.Soft && s.Total >= 19 || !s.Soft && s.Total >= a.Rules.MinStick():
+		return ActionStand
+	default:
+		return ActionHit
+	}
+}
+
+// available reports whether the hand can actually take action.
+func (a *Advisor) available(action Action, s Situation, canSplit bool) bool {
+	switch action {
+	case ActionDouble:
+		return a.canDouble(s)
+	case ActionSplit:
+		return canSplit
+	case ActionSurrender:
+		return a.canSurrender(s)
+	default:
+		return true
+	}
+}
+
+func (a *Advisor) canDouble(s Situation) bool {
+	if s.Doubled || s.Cards < 2 || s.Cards > 2 && !a.Rules.LateDouble() {
+		return false
+	}
+	return !s.Split || a.Rules.DoubleAfterSplit
+}
+
+func (a *Advisor) canSurrender(s Situation) bool {
+	return a.Rules.Surrender && s.Cards == 2 && !s.Split
+}
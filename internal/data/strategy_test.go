@@ -0,0 +1,123 @@
+package data
+
+import "testing"
+
+func newTestHand(cards ...Card) *Hand {
+	hand := NewHand()
+	for _, card := range cards {
+		hand.AddCard(card)
+	}
+	return hand
+}
+
+func TestAdvisorBasicStrategy(t *testing.T) {
+	advisor := NewAdvisor(DefaultRules())
+	tests := []struct {
+		name     string
+		hand     *Hand
+		upcard   Card
+		expected Action
+	}{
+		{"hard 16 v 10", newTestHand(Card{Spades, Ten}, Card{Hearts, Six}), Card{Clubs, Ten}, ActionHit},
+		{"hard 12 v 4", newTestHand(Card{Spades, Ten}, Card{Hearts, Two}), Card{Clubs, Four}, ActionStand},
+		{"hard 11 v A H17", newTestHand(Card{Spades, Six}, Card{Hearts, Five}), Card{Clubs, Ace}, ActionDouble},
+		{"soft 18 v 9", newTestHand(Card{Spades, Ace}, Card{Hearts, Seven}), Card{Clubs, Nine}, ActionHit},
+		{"soft 18 v 2 H17", newTestHand(Card{Spades, Ace}, Card{Hearts, Seven}), Card{Clubs, Two}, ActionDouble},
+		{"eights v A", newTestHand(Card{Spades, Eight}, Card{Hearts, Eight}), Card{Clubs, Ace}, ActionSplit},
+		{"tens v 6", newTestHand(Card{Spades, King}, Card{Hearts, Ten}), Card{Clubs, Six}, ActionStand},
+		{"fives v 9", newTestHand(Card{Spades, Five}, Card{Hearts, Five}), Card{Clubs, Nine}, ActionDouble},
+		{"three card soft 17 v 4", newTestHand(Card{Spades, Ace}, Card{Hearts, Two}, Card{Clubs, Four}), Card{Clubs, Four}, ActionHit},
+		{"three card 11 v 6", newTestHand(Card{Spades, Four}, Card{Hearts, Two}, Card{Clubs, Five}), Card{Clubs, Six}, ActionHit},
+	}
+
+	for _, test := range tests {
+		if got := advisor.Recommend(test.hand, test.upcard, true, 0); got != test.expected {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestAdvisorRuleVariations(t *testing.T) {
+	rules := DefaultRules()
+	rules.DealerHitsSoft17 = false
+	rules.DoubleAfterSplit = false
+	rules.Surrender = true
+	advisor := NewAdvisor(rules)
+
+	if got := advisor.Basic(newTestHand(Card{Spades, Six}, Card{Hearts, Five}), Card{Clubs, Ace}, true); got != ActionHit {
+		t.Errorf("11 v A under S17: got %v, want hit", got)
+	}
+	if got := advisor.Basic(newTestHand(Card{Spades, Two}, Card{Hearts, Two}), Card{Clubs, Two}, true); got != ActionHit {
+		t.Errorf("2,2 v 2 without DAS: got %v, want hit", got)
+	}
+	if got := advisor.Basic(newTestHand(Card{Spades, Ten}, Card{Hearts, Six}), Card{Clubs, Ten}, true); got != ActionSurrender {
+		t.Errorf("16 v 10 with surrender: got %v, want surrender", got)
+	}
+	if got := advisor.Basic(newTestHand(Card{Spades, Eight}, Card{Hearts, Eight}), Card{Clubs, Ten}, false); got != ActionSurrender {
+		t.Errorf("8,8 v 10 when split is unavailable: got %v, want surrender", got)
+	}
+}
+
+func TestAdvisorH17SurrenderOnlyHard17(t *testing.T) {
+	rules := DefaultRules()
+	rules.Surrender = true
+	advisor := NewAdvisor(rules)
+
+	if got := advisor.Basic(newTestHand(Card{Spades, Ten}, Card{Hearts, Seven}), Card{Clubs, Ace}, true); got != ActionSurrender {
+		t.Errorf("17 v A under H17: got %v, want surrender", got)
+	}
+	if got := advisor.Basic(newTestHand(Card{Spades, Ten}, Card{Hearts, Queen}), Card{Clubs, Ace}, true); got != ActionStand {
+		t.Errorf("20 v A under H17: got %v, want stand", got)
+	}
+}
+
+func TestAdvisorIndexPlays(t *testing.T) {
+	advisor := NewAdvisor(DefaultRules())
+	table, err := DefaultIndexTable(HiLo)
+	if err != nil {
+		t.Fatalf("unexpected index table error: %v", err)
+	}
+	advisor.Deviations = table
+
+	sixteen := newTestHand(Card{Spades, Ten}, Card{Hearts, Six})
+	ten := Card{Clubs, Ten}
+	if got := advisor.Recommend(sixteen, ten, true, -1); got != ActionHit {
+		t.Errorf("16 v 10 at TC -1: got %v, want hit", got)
+	}
+	action, play := advisor.RecommendWithReason(sixteen, ten, true, 0)
+	if action != ActionStand || play == nil || play.Name != "16 v 10" {
+		t.Errorf("16 v 10 at TC 0: got %v via %v, want stand via 16 v 10", action, play)
+	}
+
+	twelve := newTestHand(Card{Spades, Ten}, Card{Hearts, Two})
+	if got := advisor.Recommend(twelve, Card{Clubs, Four}, true, -1); got != ActionHit {
+		t.Errorf("12 v 4 at TC -1: got %v, want hit", got)
+	}
+
+	// Surrender is not offered, so the Fab 4 entry falls through to the
+	// Illustrious 18 stand.
+	fifteen := newTestHand(Card{Spades, Ten}, Card{Hearts, Five})
+	if got := advisor.Recommend(fifteen, ten, true, 4); got != ActionStand {
+		t.Errorf("15 v 10 at TC +4 without surrender: got %v, want stand", got)
+	}
+	advisor.Rules.Surrender = true
+	if got := advisor.Recommend(fifteen, ten, true, 0); got != ActionSurrender {
+		t.Errorf("15 v 10 at TC 0 with surrender: got %v, want surrender", got)
+	}
+	if action, play := advisor.RecommendWithReason(sixteen, ten, true, 2); action != ActionSurrender || play != nil {
+		t.Errorf("16 v 10 at TC +2 with surrender: got %v via %v, want basic strategy surrender", action, play)
+	}
+
+	tens := newTestHand(Card{Spades, King}, Card{Hearts, Queen})
+	if got := advisor.Recommend(tens, Card{Clubs, Six}, true, 4); got != ActionSplit {
+		t.Errorf("10,10 v 6 at TC +4: got %v, want split", got)
+	}
+	if got := advisor.Recommend(tens, Card{Clubs, Six}, false, 4); got != ActionStand {
+		t.Errorf("10,10 v 6 when split is unavailable: got %v, want stand", got)
+	}
+
+	eights := newTestHand(Card{Spades, Eight}, Card{Hearts, Eight})
+	if got := advisor.Recommend(eights, ten, true, 5); got != ActionSplit {
+		t.Errorf("8,8 v 10 at TC +5: got %v, want split", got)
+	}
+}
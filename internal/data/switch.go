@@ -0,0 +1,93 @@
+package data
+
+import "errors"
+
+var ErrSwitchDecided = errors.New("player has already chosen whether to switch")
+
+// Rough values of two-card starting hands against an average upcard, used
+// to rank the two arrangements of a Blackjack Switch deal. Pairs that play
+// better split than as their total are listed by card value.
+var (
+	hardStartValues = map[int]float64{
+		4: -0.08, 5: -0.12, 6: -0.13, 7: -0.11, 8: -0.02, 9: 0.07, 10: 0.18, 11: 0.23,
+		12: -0.21, 13: -0.25, 14: -0.30, 15: -0.35, 16: -0.39, 17: -0.15, 18: 0.00,
+		19: 0.28, 20: 0.55,
+	}
+	softStartValues = map[int]float64{
+		13: 0.05, 14: 0.02, 15: 0.00, 16: -0.02, 17: 0.00, 18: 0.10, 19: 0.30, 20: 0.55,
+	}
+	pairStartValues = map[int]float64{11: 0.20, 8: -0.05, 9: 0.10}
+)
+
+func startValue(hand *Hand) float64 {
+	cards := hand.Cards()
+	switch {
+	case hand.IsBlackjack():
+		return 1
+	case cards[0].Rank == cards[1].Rank && pairStartValues[cards[0].Value()] != 0:
+		return pairStartValues[cards[0].Value()]
+	case hand.IsSoft():
+		return softStartValues[hand.Value()]
+	default:
+		return hardStartValues[hand.Value()]
+	}
+}
+
+// SwitchedHands returns copies of two boxes with their second cards
+// swapped.
+func SwitchedHands(first, second *Hand) (*Hand, *Hand) {
+	a, b := first.Cards(), second.Cards()
+	return newSwitchHand(a[0], b[1], first.Bet()), newSwitchHand(b[0], a[1], second.Bet())
+}
+
+func newSwitchHand(first, second Card, bet int) *Hand {
+	hand := NewHand()
+	hand.AddCard(first)
+	hand.AddCard(second)
+	hand.SetBet(bet)
+	return hand
+}
+
+// ShouldSwitch reports whether swapping the second cards of two freshly
+// dealt boxes makes the stronger pair of hands.
+func ShouldSwitch(first, second *Hand) bool {
+	a, b := SwitchedHands(first, second)
+	return startValue(a)+startValue(b) > startValue(first)+startValue(second)
+}
+
+// Switch records a player's choice in the switch phase: swap the second
+// cards of their two boxes or keep them as dealt. Play starts once every
+// player has chosen.
+func (g *Game) Switch(player *Player, swap bool) error {
+	if g.state != StateSwitch {
+		return ErrInvalidState
+	}
+	if !g.containsPlayer(player) {
+		return ErrUnknownPlayer
+	}
+	if player.Status() != PlayerStatusWaiting {
+		return ErrSwitchDecided
+	}
+	if swap {
+		first, second := player.hands[0].cards, player.hands[1].cards
+		first[1], second[1] = second[1], first[1]
+	}
+	player.SetStatus(PlayerStatusActing)
+	for _, other := range g.players {
+		if other.Status() == PlayerStatusWaiting {
+			return nil
+		}
+	}
+	g.beginPlay()
+	return nil
+}
+
+// switchAgents makes the switch choice for every agent seat.
+func (g *Game) switchAgents() {
+	for _, player := range g.players {
+		if player.Agent() != nil {
+			hands := player.Hands()
+			g.Switch(player, ShouldSwitch(hands[0], hands[1]))
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package data
+
+import (
+	"errors"
+	"testing"
+)
+
+func switchRules() Rules {
+	rules := DefaultRules()
+	rules.Variant = BlackjackSwitch
+	return rules
+}
+
+func TestShouldSwitch(t *testing.T) {
+	first := newTestHand(Card{Spades, Ten}, Card{Hearts, Ten})
+	second := newTestHand(Card{Clubs, Nine}, Card{Diamonds, Nine})
+	if ShouldSwitch(first, second) {
+		t.Error("expected 20 and a pair of nines to be kept over two 19s")
+	}
+	first = newTestHand(Card{Spades, Ten}, Card{Hearts, Six})
+	second = newTestHand(Card{Clubs, Ace}, Card{Diamonds, Five})
+	if !ShouldSwitch(first, second) {
+		t.Error("expected 16 and soft 16 to switch into 15 and blackjack")
+	}
+	a, b := SwitchedHands(first, second)
+	if a.String() != "10♠ 5♦" || b.String() != "A♣ 6♥" {
+		t.Errorf("unexpected switched hands %s and %s", a, b)
+	}
+}
+
+func TestGameSwitchPhase(t *testing.T) {
+	game, err := NewGameWithRules(switchRules(), []PlayerConfig{{Name: "Alice", Bankroll: 100}, {Name: "Bob", Bankroll: 100}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.deck.cards = []Card{
+		{Suit: Spades, Rank: Ten},    // Alice box 1 card 1
+		{Suit: Clubs, Rank: Ace},     // Alice box 2 card 1
+		{Suit: Spades, Rank: Seven},  // Bob box 1 card 1
+		{Suit: Spades, Rank: Eight},  // Bob box 2 card 1
+		{Suit: Clubs, Rank: Six},     // dealer upcard
+		{Suit: Diamonds, Rank: King}, // Alice box 1 card 2
+		{Suit: Hearts, Rank: Six},    // Alice box 2 card 2
+		{Suit: Hearts, Rank: Queen},  // Bob box 1 card 2
+		{Suit: Hearts, Rank: Jack},   // Bob box 2 card 2
+		{Suit: Hearts, Rank: Ten},    // hole card
+		{Suit: Spades, Rank: Six},    // dealer draw to 22
+	}
+	player := game.Players()[0]
+	if err := game.StartRound(map[string]int{"Alice": 10, "Bob": 10}); err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	if len(player.Hands()) != 2 || player.Bankroll() != 80 {
+		t.Fatalf("expected two $10 boxes, got %d hands and bankroll %d", len(player.Hands()), player.Bankroll())
+	}
+	if err := game.DealInitialCards(); err != nil {
+		t.Fatalf("unexpected deal error: %v", err)
+	}
+	if game.State() != StateSwitch {
+		t.Fatalf("expected the switch phase, got %v", game.State())
+	}
+	if _, err := game.Hit(player); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected play to wait for the switch, got %v", err)
+	}
+	if err := game.Switch(player, true); err != nil {
+		t.Fatalf("unexpected switch error: %v", err)
+	}
+	if err := game.Switch(player, false); !errors.Is(err, ErrSwitchDecided) {
+		t.Fatalf("expected a second choice to fail, got %v", err)
+	}
+	if game.State() != StateSwitch {
+		t.Fatalf("expected the switch phase to wait for Bob, got %v", game.State())
+	}
+	bob := game.Players()[1]
+	if err := game.Switch(bob, false); err != nil {
+		t.Fatalf("unexpected switch error: %v", err)
+	}
+	hands := player.Hands()
+	if hands[0].String() != "10♠ 6♥" || !hands[1].IsBlackjack() {
+		t.Fatalf("expected 16 and a blackjack after the switch, got %s and %s", hands[0], hands[1])
+	}
+	if game.State() != StatePlayerAction {
+		t.Fatalf("expected play to start, got %v", game.State())
+	}
+	if err := game.Stand(player); err != nil {
+		t.Fatalf("unexpected stand error: %v", err)
+	}
+	if err := game.Stand(player); err != nil {
+		t.Fatalf("unexpected stand error: %v", err)
+	}
+	for range 2 {
+		if err := game.Stand(bob); err != nil {
+			t.Fatalf("unexpected stand error: %v", err)
+		}
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected every box done")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer error: %v", err)
+	}
+	results, err := game.SettleRound()
+	if err != nil {
+		t.Fatalf("unexpected settle error: %v", err)
+	}
+	if results[0].Outcome != OutcomePush || results[1].Outcome != OutcomeBlackjack {
+		t.Fatalf("expected the dealer 22 to push the 16 but not the blackjack, got %v and %v", results[0].Outcome, results[1].Outcome)
+	}
+	if player.Bankroll() != 80+10+20 {
+		t.Fatalf("expected the blackjack paid even money, got bankroll %d", player.Bankroll())
+	}
+}
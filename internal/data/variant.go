@@ -0,0 +1,308 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Variant picks the game the table deals. Variants reuse the standard
+// engine and differ in the shoe, the payouts and the plays on offer.
+type Variant int
+
+const (
+	Standard Variant = iota
+	// Spanish21 deals from decks without the tens. A player 21 always
+	// wins, some 21s pay a bonus, and a hand may double on any number of
+	// cards and then be rescued by surrendering the original bet.
+	Spanish21
+	// BlackjackSwitch deals every player two boxes and lets them swap the
+	// second cards between them. A dealer 22 pushes everything but a
+	// blackjack, which pays even money.
+	BlackjackSwitch
+	// DoubleExposure deals both dealer cards face up. The dealer wins
+	// ties other than tied blackjacks, and blackjack pays even money.
+	DoubleExposure
+	// FreeBet has the house fund doubles of hard 9, 10 and 11 and splits
+	// of every pair but tens. A dealer 22 pushes everything but a
+	// blackjack.
+	FreeBet
+	// Pontoon is the British game, where players twist, stick and buy
+	// rather than hit, stand and double. Both dealer cards are dealt face
+	// down, a hand needs 15 to stick, five cards without busting make a
+	// trick, pontoons and tricks pay 2:1, and the dealer wins every tie.
+	Pontoon
+)
+
+var variantNames = []string{"Standard", "Spanish 21", "Blackjack Switch", "Double Exposure", "Free Bet", "Pontoon"}
+
+func (v Variant) String() string {
+	if v < 0 || int(v) >= len(variantNames) {
+		return fmt.Sprintf("Variant(%d)", int(v))
+	}
+	return variantNames[v]
+}
+
+// ParseVariant reads a variant name, ignoring case and spaces.
+func ParseVariant(s string) (Variant, error) {
+	for i := range variantNames {
+		if flagName(variantNames[i]) == flagName(s) {
+			return Variant(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown variant %q (want one of %s)", s, strings.Join(VariantNames(), ", "))
+}
+
+// VariantNames lists the names ParseVariant accepts.
+func VariantNames() []string {
+	names := make([]string, len(variantNames))
+	for i, name := range variantNames {
+		names[i] = flagName(name)
+	}
+	return names
+}
+
+func flagName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", ""))
+}
+
+// Ranks lists the ranks in each deck of the shoe.
+func (v Variant) Ranks() []Rank {
+	var ranks []Rank
+	for rank := Ace; rank <= King; rank++ {
+		if v == Spanish21 && rank == Ten {
+			continue
+		}
+		ranks = append(ranks, rank)
+	}
+	return ranks
+}
+
+// DeckSize is the number of cards in each deck of the shoe.
+func (r Rules) DeckSize() int {
+	return 4 * len(r.Variant.Ranks())
+}
+
+// LateDouble reports whether a hand may double on more than two cards.
+func (r Rules) LateDouble() bool {
+	return r.Variant == Spanish21 || r.Variant == Pontoon
+}
+
+// Buys reports whether a double is a Pontoon buy: the hand takes its card
+// and stays open to twist or stick.
+func (r Rules) Buys() bool {
+	return r.Variant == Pontoon
+}
+
+// MinStick is the lowest total a hand may stand on.
+func (r Rules) MinStick() int {
+	if r.Variant == Pontoon {
+		return 15
+	}
+	return 0
+}
+
+// CharlieCards is the number of cards that win a hand outright, beating any
+// dealer hand but a blackjack, or zero when there is no Charlie. Pontoon
+// always has its five-card trick.
+func (r Rules) CharlieCards() int {
+	if r.Variant == Pontoon {
+		return 5
+	}
+	return r.Charlie
+}
+
+// DoubleRescue reports whether a doubled hand may take back the double and
+// forfeit the original bet instead of standing.
+func (r Rules) DoubleRescue() bool {
+	return r.Variant == Spanish21
+}
+
+// Boxes is the number of hands each player bets on at the start of a
+// round.
+func (r Rules) Boxes() int {
+	if r.Variant == BlackjackSwitch {
+		return 2
+	}
+	return 1
+}
+
+// MaxHands is the most hands a player may hold after splitting, across all
+// their boxes.
+func (r Rules) MaxHands() int {
+	return r.MaxSplitHands * r.Boxes()
+}
+
+// BlackjackPays returns what a blackjack pays as num to den.
+func (r Rules) BlackjackPays() (num, den int) {
+	switch r.Variant {
+	case BlackjackSwitch, DoubleExposure:
+		return 1, 1
+	case Pontoon:
+		return 2, 1
+	default:
+		return 3, 2
+	}
+}
+
+// HoleCardExposed reports whether the dealer's second card is dealt face
+// up.
+func (r Rules) HoleCardExposed() bool {
+	return r.Variant == DoubleExposure
+}
+
+// UpcardHidden reports whether the dealer's first card is dealt face down
+// too.
+func (r Rules) UpcardHidden() bool {
+	return r.Variant == Pontoon
+}
+
+// DealerWinsTies reports whether a tie loses. Double Exposure still pushes
+// tied blackjacks.
+func (r Rules) DealerWinsTies() bool {
+	return r.Variant == DoubleExposure || r.Variant == Pontoon
+}
+
+// Dealer22Pushes reports whether a dealer 22 pushes every hand that is not
+// a blackjack.
+func (r Rules) Dealer22Pushes() bool {
+	return r.Variant == BlackjackSwitch || r.Variant == FreeBet
+}
+
+// FreeDouble reports whether the house funds a double of hand: a two-card
+// hard 9, 10 or 11 at a Free Bet table.
+func (r Rules) FreeDouble(hand *Hand) bool {
+	value := hand.Value()
+	return r.Variant == FreeBet && len(hand.Cards()) == 2 && !hand.IsSoft() && value >= 9 && value <= 11
+}
+
+// FreeSplit reports whether the house funds a split of hand: any pair but
+// tens at a Free Bet table.
+func (r Rules) FreeSplit(hand *Hand) bool {
+	return r.Variant == FreeBet && hand.CanSplit() && hand.Cards()[0].Value() != 10
+}
+
+// SplitAceDone reports whether hand is a split ace that has had its one
+// card and may not draw, double or split again.
+func (r Rules) SplitAceDone(hand *Hand) bool {
+	return !r.HitSplitAces && hand.IsSplit() && hand.Cards()[0].Rank == Ace
+}
+
+// Bonus is a hand paid above even money: the Spanish 21 bonuses and the
+// Pontoon five-card trick.
+type Bonus int
+
+const (
+	NoBonus Bonus = iota
+	FiveCard21
+	SixCard21
+	SevenCard21
+	Mixed678
+	Suited678
+	Spades678
+	Mixed777
+	Suited777
+	Spades777
+	FiveCardTrick
+)
+
+var bonusNames = []string{
+	"", "five-card 21", "six-card 21", "seven-card 21",
+	"6-7-8", "suited 6-7-8", "spade 6-7-8", "7-7-7", "suited 7-7-7", "spade 7-7-7",
+	"five-card trick",
+}
+
+func (b Bonus) String() string {
+	if b < 0 || int(b) >= len(bonusNames) {
+		return fmt.Sprintf("Bonus(%d)", int(b))
+	}
+	return bonusNames[b]
+}
+
+// Pays returns what the bonus pays as num to den.
+func (b Bonus) Pays() (num, den int) {
+	switch b {
+	case FiveCard21, Mixed678, Mixed777:
+		return 3, 2
+	case SixCard21, Suited678, Suited777, FiveCardTrick:
+		return 2, 1
+	case SevenCard21, Spades678, Spades777:
+		return 3, 1
+	default:
+		return 1, 1
+	}
+}
+
+// SpanishBonus finds the bonus a winning 21 earns. Doubled hands earn none.
+func SpanishBonus(hand *Hand) Bonus {
+	cards := hand.Cards()
+	if hand.Value() != 21 || hand.IsDoubleDown() || hand.IsBlackjack() {
+		return NoBonus
+	}
+	switch {
+	case len(cards) >= 7:
+		return SevenCard21
+	case len(cards) == 6:
+		return SixCard21
+	case len(cards) == 5:
+		return FiveCard21
+	case len(cards) != 3:
+		return NoBonus
+	}
+	suited := cards[0].Suit == cards[1].Suit && cards[1].Suit == cards[2].Suit
+	spades := suited && cards[0].Suit == Spades
+	sevens := cards[0].Rank == Seven && cards[1].Rank == Seven && cards[2].Rank == Seven
+	var ranks [14]bool
+	for _, card := range cards {
+		ranks[card.Rank] = true
+	}
+	switch {
+	case sevens && spades:
+		return Spades777
+	case sevens && suited:
+		return Suited777
+	case sevens:
+		return Mixed777
+	case !ranks[Six] || !ranks[Seven] || !ranks[Eight]:
+		return NoBonus
+	case spades:
+		return Spades678
+	case suited:
+		return Suited678
+	default:
+		return Mixed678
+	}
+}
+
+// settle applies the variant's payouts to a hand determineOutcome judged.
+// A Charlie beats anything but a dealer blackjack, and pays 2:1 as a Pontoon
+// trick. In Spanish 21 a player's 21 wins even against a dealer 21, and a
+// winning 21 may earn a bonus.
+func (r Rules) settle(hand, dealer *Hand, outcome HandOutcome) (HandOutcome, Bonus) {
+	if charlie := r.CharlieCards(); charlie > 0 && len(hand.Cards()) >= charlie && !hand.IsBusted() && !dealer.IsBlackjack() {
+		switch r.Variant {
+		case Pontoon:
+			return OutcomeCharlie, FiveCardTrick
+		case Spanish21:
+			return OutcomeCharlie, SpanishBonus(hand)
+		default:
+			return OutcomeCharlie, NoBonus
+		}
+	}
+	if r.Dealer22Pushes() && dealer.Value() == 22 && outcome == OutcomeWin {
+		return OutcomePush, NoBonus
+	}
+	if r.DealerWinsTies() && outcome == OutcomePush && (!hand.IsBlackjack() || r.Variant == Pontoon) {
+		return OutcomeLose, NoBonus
+	}
+	if r.Variant != Spanish21 || hand.IsBusted() || hand.IsSurrendered() {
+		return outcome, NoBonus
+	}
+	switch {
+	case hand.IsBlackjack():
+		return OutcomeBlackjack, NoBonus
+	case hand.Value() == 21:
+		return OutcomeWin, SpanishBonus(hand)
+	default:
+		return outcome, NoBonus
+	}
+}
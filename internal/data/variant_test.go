@@ -0,0 +1,293 @@
+package data
+
+import (
+	"errors"
+	"testing"
+)
+
+func spanishRules() Rules {
+	rules := DefaultRules()
+	rules.Variant = Spanish21
+	return rules
+}
+
+func TestParseVariant(t *testing.T) {
+	for _, name := range []string{"spanish21", "Spanish 21"} {
+		if v, err := ParseVariant(name); err != nil || v != Spanish21 {
+			t.Errorf("ParseVariant(%q) = %v, %v", name, v, err)
+		}
+	}
+	if _, err := ParseVariant("baccarat"); err == nil {
+		t.Error("expected an unknown variant to fail")
+	}
+}
+
+func TestSpanishShoeHasNoTens(t *testing.T) {
+	game, err := NewGameWithRules(spanishRules(), []PlayerConfig{{Name: "Alice", Bankroll: 100}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	if left := game.Deck().CardsLeft(); left != 6*48 {
+		t.Fatalf("expected six 48-card decks, got %d cards", left)
+	}
+	counts := game.Deck().RankCounts()
+	if counts[Ten] != 0 || counts[King] != 24 {
+		t.Fatalf("expected no tens and every face card, got %v", counts)
+	}
+}
+
+func TestSpanishBonus(t *testing.T) {
+	tests := []struct {
+		name string
+		hand *Hand
+		want Bonus
+	}{
+		{"mixed 6-7-8", newTestHand(Card{Spades, Six}, Card{Hearts, Seven}, Card{Clubs, Eight}), Mixed678},
+		{"suited 6-7-8", newTestHand(Card{Hearts, Eight}, Card{Hearts, Six}, Card{Hearts, Seven}), Suited678},
+		{"spade 7-7-7", newTestHand(Card{Spades, Seven}, Card{Spades, Seven}, Card{Spades, Seven}), Spades777},
+		{"five cards", newTestHand(Card{Spades, Two}, Card{Hearts, Three}, Card{Clubs, Four}, Card{Clubs, Five}, Card{Clubs, Seven}), FiveCard21},
+		{"seven cards", newTestHand(Card{Spades, Ace}, Card{Hearts, Two}, Card{Clubs, Two}, Card{Clubs, Three}, Card{Clubs, Four}, Card{Clubs, Four}, Card{Spades, Five}), SevenCard21},
+		{"three-card 21", newTestHand(Card{Spades, Five}, Card{Hearts, Seven}, Card{Clubs, Nine}), NoBonus},
+		{"blackjack", newTestHand(Card{Spades, Ace}, Card{Hearts, King}), NoBonus},
+	}
+	for _, test := range tests {
+		if got := SpanishBonus(test.hand); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSpanishTwentyOneWins(t *testing.T) {
+	rules := spanishRules()
+	hand := newTestHand(Card{Spades, Five}, Card{Hearts, Seven}, Card{Clubs, Nine})
+	dealer21 := newTestHand(Card{Diamonds, Queen}, Card{Diamonds, Four}, Card{Diamonds, Seven})
+	if outcome, _ := rules.settle(hand, dealer21, OutcomePush); outcome != OutcomeWin {
+		t.Errorf("expected a 21 to beat the dealer's 21, got %v", outcome)
+	}
+	blackjack := newTestHand(Card{Spades, Ace}, Card{Hearts, King})
+	if outcome, _ := rules.settle(blackjack, dealer21, OutcomeLose); outcome != OutcomeBlackjack {
+		t.Errorf("expected a blackjack to beat the dealer's blackjack, got %v", outcome)
+	}
+	if outcome, _ := DefaultRules().settle(hand, dealer21, OutcomePush); outcome != OutcomePush {
+		t.Errorf("expected a standard 21 to push a dealer 21, got %v", outcome)
+	}
+
+	game, err := NewGameWithRules(rules, []PlayerConfig{{Name: "Alice", Bankroll: 100}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.deck.cards = []Card{
+		{Suit: Spades, Rank: Seven},   // player card 1
+		{Suit: Clubs, Rank: King},     // dealer upcard
+		{Suit: Spades, Rank: Seven},   // player card 2
+		{Suit: Diamonds, Rank: Eight}, // hole card
+		{Suit: Spades, Rank: Seven},   // player hit
+	}
+	player := game.Players()[0]
+	if err := game.StartRound(map[string]int{"Alice": 10}); err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	if err := game.DealInitialCards(); err != nil {
+		t.Fatalf("unexpected deal error: %v", err)
+	}
+	if _, err := game.Hit(player); err != nil {
+		t.Fatalf("unexpected hit error: %v", err)
+	}
+	if err := game.Stand(player); err != nil {
+		t.Fatalf("unexpected stand error: %v", err)
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected the round to be ready for the dealer")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer error: %v", err)
+	}
+	results, err := game.SettleRound()
+	if err != nil {
+		t.Fatalf("unexpected settle error: %v", err)
+	}
+	if results[0].Outcome != OutcomeWin || results[0].Bonus != Spades777 {
+		t.Fatalf("expected a spade 7-7-7 win, got %v with %v", results[0].Outcome, results[0].Bonus)
+	}
+	if player.Bankroll() != 130 {
+		t.Fatalf("expected the bonus paid 3:1, got bankroll %d", player.Bankroll())
+	}
+}
+
+func TestSpanishLateDoubleAndRescue(t *testing.T) {
+	game, err := NewGameWithRules(spanishRules(), []PlayerConfig{{Name: "Alice", Bankroll: 100}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.deck.cards = []Card{
+		{Suit: Spades, Rank: Two},     // player card 1
+		{Suit: Clubs, Rank: King},     // dealer upcard
+		{Suit: Hearts, Rank: Three},   // player card 2
+		{Suit: Diamonds, Rank: Seven}, // hole card
+		{Suit: Clubs, Rank: Four},     // player hit
+		{Suit: Diamonds, Rank: Five},  // double card
+	}
+	player := game.Players()[0]
+	if err := game.StartRound(map[string]int{"Alice": 10}); err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	if err := game.DealInitialCards(); err != nil {
+		t.Fatalf("unexpected deal error: %v", err)
+	}
+	if _, err := game.Hit(player); err != nil {
+		t.Fatalf("unexpected hit error: %v", err)
+	}
+	if _, err := game.DoubleDown(player); err != nil {
+		t.Fatalf("expected a three-card double, got %v", err)
+	}
+	hand := player.ActiveHand()
+	if hand.IsStanding() || hand.Bet() != 20 {
+		t.Fatalf("expected the doubled hand open for a rescue, got standing %v bet %d", hand.IsStanding(), hand.Bet())
+	}
+	advisor := NewAdvisor(game.Rules())
+	if got := advisor.Recommend(hand, Card{Clubs, King}, false, 0); got != ActionSurrender {
+		t.Fatalf("expected a rescue of 14 against a king, got %v", got)
+	}
+	if _, err := game.Hit(player); !errors.Is(err, ErrHandDoubled) {
+		t.Fatalf("expected the doubled hand to refuse a hit, got %v", err)
+	}
+	if err := game.Surrender(player); err != nil {
+		t.Fatalf("unexpected rescue error: %v", err)
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected the rescue to finish the hand")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer error: %v", err)
+	}
+	results, err := game.SettleRound()
+	if err != nil {
+		t.Fatalf("unexpected settle error: %v", err)
+	}
+	if results[0].Outcome != OutcomeSurrender || player.Bankroll() != 90 {
+		t.Fatalf("expected only the original bet lost, got %v with bankroll %d", results[0].Outcome, player.Bankroll())
+	}
+}
+
+func TestAdvisorSpanishStrategy(t *testing.T) {
+	advisor := NewAdvisor(spanishRules())
+	tests := []struct {
+		name     string
+		hand     *Hand
+		upcard   Card
+		expected Action
+	}{
+		{"9 v 3", newTestHand(Card{Spades, Four}, Card{Hearts, Five}), Card{Clubs, Three}, ActionHit},
+		{"11 v A", newTestHand(Card{Spades, Six}, Card{Hearts, Five}), Card{Clubs, Ace}, ActionDouble},
+		{"three card 11 v 6", newTestHand(Card{Spades, Four}, Card{Hearts, Two}, Card{Clubs, Five}), Card{Clubs, Six}, ActionDouble},
+		{"13 v 2", newTestHand(Card{Spades, King}, Card{Hearts, Three}), Card{Clubs, Two}, ActionHit},
+		{"fours v 5", newTestHand(Card{Spades, Four}, Card{Hearts, Four}), Card{Clubs, Five}, ActionHit},
+	}
+	for _, test := range tests {
+		if got := advisor.Recommend(test.hand, test.upcard, true, 0); got != test.expected {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.expected)
+		}
+	}
+
+	ace := Card{Clubs, Ace}
+	surrenders := []*Hand{
+		newTestHand(Card{Spades, King}, Card{Hearts, Six}),
+		newTestHand(Card{Spades, King}, Card{Hearts, Seven}),
+		newTestHand(Card{Spades, Eight}, Card{Hearts, Eight}),
+	}
+	advisor.Rules.Surrender = true
+	for _, hand := range surrenders {
+		if got := advisor.Recommend(hand, ace, true, 0); got != ActionSurrender {
+			t.Errorf("%v v A with surrender: got %v, want surrender", hand, got)
+		}
+	}
+	advisor.Rules.DealerHitsSoft17 = false
+	if got := advisor.Recommend(surrenders[1], ace, true, 0); got != ActionStand {
+		t.Errorf("17 v A under S17: got %v, want stand", got)
+	}
+}
+
+// playExposedRound deals cards to Alice at a Double Exposure table, stands
+// her hand and settles the round.
+func playExposedRound(t *testing.T, cards []Card) (*Game, RoundResult) {
+	t.Helper()
+	rules := DefaultRules()
+	rules.Variant = DoubleExposure
+	game, err := NewGameWithRules(rules, []PlayerConfig{{Name: "Alice", Bankroll: 100}})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.deck.cards = cards
+	if err := game.StartRound(map[string]int{"Alice": 10}); err != nil {
+		t.Fatalf("unexpected start round error: %v", err)
+	}
+	if err := game.DealInitialCards(); err != nil {
+		t.Fatalf("unexpected deal error: %v", err)
+	}
+	if game.Dealer().HoleCardHidden() {
+		t.Fatal("expected the hole card dealt face up")
+	}
+	if err := game.Stand(game.Players()[0]); err != nil {
+		t.Fatalf("unexpected stand error: %v", err)
+	}
+	if !game.ReadyForDealer() {
+		t.Fatal("expected the round to be ready for the dealer")
+	}
+	if err := game.DealerPlay(); err != nil {
+		t.Fatalf("unexpected dealer error: %v", err)
+	}
+	results, err := game.SettleRound()
+	if err != nil {
+		t.Fatalf("unexpected settle error: %v", err)
+	}
+	return game, results[0]
+}
+
+func TestDoubleExposure(t *testing.T) {
+	game, result := playExposedRound(t, []Card{
+		{Suit: Spades, Rank: Ten},    // player card 1
+		{Suit: Clubs, Rank: Nine},    // dealer upcard
+		{Suit: Hearts, Rank: Eight},  // player card 2
+		{Suit: Diamonds, Rank: Nine}, // face-up hole card
+	})
+	if running := game.Counter().Running(); running != -1 {
+		t.Errorf("expected the hole card counted once, got running count %d", running)
+	}
+	if result.Outcome != OutcomeLose || game.Players()[0].Bankroll() != 90 {
+		t.Errorf("expected the tie to lose, got %v with bankroll %d", result.Outcome, game.Players()[0].Bankroll())
+	}
+
+	game, result = playExposedRound(t, []Card{
+		{Suit: Spades, Rank: Ace},
+		{Suit: Clubs, Rank: Nine},
+		{Suit: Hearts, Rank: King},
+		{Suit: Diamonds, Rank: Eight},
+	})
+	if result.Outcome != OutcomeBlackjack || game.Players()[0].Bankroll() != 110 {
+		t.Errorf("expected blackjack paid even money, got %v with bankroll %d", result.Outcome, game.Players()[0].Bankroll())
+	}
+}
+
+func TestAdvisorDoubleExposure(t *testing.T) {
+	rules := DefaultRules()
+	rules.Variant = DoubleExposure
+	advisor := NewAdvisor(rules)
+	tests := []struct {
+		name     string
+		hand     *Hand
+		dealer   *Hand
+		expected Action
+	}{
+		{"18 v 18", newTestHand(Card{Spades, Ten}, Card{Hearts, Eight}), newTestHand(Card{Clubs, Ten}, Card{Clubs, Eight}), ActionHit},
+		{"19 v 18", newTestHand(Card{Spades, Ten}, Card{Hearts, Nine}), newTestHand(Card{Clubs, Ten}, Card{Clubs, Eight}), ActionStand},
+		{"12 v 16", newTestHand(Card{Spades, Ten}, Card{Hearts, Two}), newTestHand(Card{Clubs, Ten}, Card{Clubs, Six}), ActionStand},
+		{"tens v 14", newTestHand(Card{Spades, Ten}, Card{Hearts, King}), newTestHand(Card{Clubs, Eight}, Card{Clubs, Six}), ActionSplit},
+		{"soft 19 v 15", newTestHand(Card{Spades, Ace}, Card{Hearts, Eight}), newTestHand(Card{Clubs, Nine}, Card{Clubs, Six}), ActionDouble},
+		{"16 v 10", newTestHand(Card{Spades, Ten}, Card{Hearts, Six}), newTestHand(Card{Clubs, Six}, Card{Clubs, Four}), ActionHit},
+	}
+	for _, test := range tests {
+		if got, _ := advisor.RecommendAgainst(test.hand, test.dealer, true, 0); got != test.expected {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.expected)
+		}
+	}
+}
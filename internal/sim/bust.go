@@ -0,0 +1,180 @@
+package sim
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+
+	"blackjack/internal/data"
+)
+
+// True counts outside this range are tallied with the nearest end.
+const (
+	minBustCount = -6
+	maxBustCount = 6
+)
+
+// BustBucket tallies the dealer's finished hands at one true count.
+// Busts[i] counts busts with i+3 cards, the last entry eight or more.
+type BustBucket struct {
+	TrueCount int
+	Rounds    int
+	Busts     [6]int
+}
+
+func (b *BustBucket) merge(other BustBucket) {
+	b.Rounds += other.Rounds
+	for i, n := range other.Busts {
+		b.Busts[i] += n
+	}
+}
+
+// BustRate is the share of rounds the dealer busted.
+func (b BustBucket) BustRate() float64 {
+	if b.Rounds == 0 {
+		return 0
+	}
+	busts := 0
+	for _, n := range b.Busts {
+		busts += n
+	}
+	return float64(busts) / float64(b.Rounds)
+}
+
+// Edge returns the player's result per unit bet on the dealer bust with
+// table, and its standard error.
+func (b BustBucket) Edge(table data.Paytable) (float64, float64) {
+	if b.Rounds == 0 {
+		return 0, 0
+	}
+	n := float64(b.Rounds)
+	losses := n
+	var sum, sumSq float64
+	for i, count := range b.Busts {
+		odds := float64(table[data.BustThreeCards+data.SideOutcome(i)])
+		if odds <= 0 {
+			continue
+		}
+		losses -= float64(count)
+		sum += float64(count) * odds
+		sumSq += float64(count) * odds * odds
+	}
+	sum -= losses
+	sumSq += losses
+	mean := sum / n
+	if b.Rounds < 2 {
+		return mean, 0
+	}
+	variance := (sumSq - n*mean*mean) / (n - 1)
+	return mean, math.Sqrt(math.Max(variance, 0) / n)
+}
+
+// DealerBustResult is a dealer bust bet placed on every round, tallied by
+// the true count the round was bet at.
+type DealerBustResult struct {
+	Paytable data.Paytable
+	// Buckets run from the lowest true count to the highest.
+	Buckets []BustBucket
+	// Main tallies the main bets played alongside.
+	Main Result
+}
+
+// Total merges every bucket.
+func (r DealerBustResult) Total() BustBucket {
+	var total BustBucket
+	for _, b := range r.Buckets {
+		total.merge(b)
+	}
+	return total
+}
+
+// SimulateDealerBust plays cfg.Rounds rounds with the fast engine and
+// settles a one-unit dealer bust bet with table on each. The main bet is
+// sized and played as cfg says; agents are not supported.
+func SimulateDealerBust(cfg Config, table data.Paytable) (DealerBustResult, error) {
+	if err := cfg.Rules.Validate(); err != nil {
+		return DealerBustResult{}, err
+	}
+	if err := checkFast(cfg.Rules); err != nil {
+		return DealerBustResult{}, err
+	}
+	if cfg.Rounds <= 0 {
+		return DealerBustResult{}, fmt.Errorf("number of rounds must be positive")
+	}
+	cfg = cfg.withDefaults()
+
+	buckets := make([][]BustBucket, cfg.Workers)
+	mains := make([]Result, cfg.Workers)
+	var wg sync.WaitGroup
+	for worker := range cfg.Workers {
+		rounds := cfg.Rounds / cfg.Workers
+		if worker < cfg.Rounds%cfg.Workers {
+			rounds++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tally := make([]BustBucket, maxBustCount-minBustCount+1)
+			engine := NewFastEngine(cfg, cfg.Seed+int64(worker))
+			engine.dealerDone = func(tc float64, cards int, busted bool) {
+				b := &tally[min(max(int(math.Floor(tc)), minBustCount), maxBustCount)-minBustCount]
+				b.Rounds++
+				if outcome := data.DealerBustOutcome(cards, busted); outcome != data.SideNoWin {
+					b.Busts[outcome-data.BustThreeCards]++
+				}
+			}
+			for range rounds {
+				engine.PlayRound(&mains[worker])
+			}
+			buckets[worker] = tally
+		}()
+	}
+	wg.Wait()
+
+	r := DealerBustResult{Paytable: table, Buckets: make([]BustBucket, maxBustCount-minBustCount+1)}
+	for i := range r.Buckets {
+		r.Buckets[i].TrueCount = minBustCount + i
+	}
+	for worker := range cfg.Workers {
+		for i, b := range buckets[worker] {
+			r.Buckets[i].merge(b)
+		}
+		r.Main.Merge(mains[worker])
+	}
+	return r, nil
+}
+
+// ReportDealerBust prints the bet's edge overall and at each true count.
+func ReportDealerBust(w io.Writer, cfg Config, r DealerBustResult) {
+	cfg = cfg.withDefaults()
+	var pays []string
+	for _, outcome := range data.SideDealerBust.Outcomes() {
+		pays = append(pays, fmt.Sprintf("%d", r.Paytable[outcome]))
+	}
+	total := r.Total()
+	edge, stdErr := total.Edge(r.Paytable)
+	fmt.Fprintf(w, "Rules:      %s, %.0f%% penetration\n", cfg.Rules, cfg.Rules.Penetration*100)
+	fmt.Fprintf(w, "Strategy:   %s, counting %s\n", strategyName(cfg), cfg.System.Name)
+	fmt.Fprintf(w, "Paytable:   %s to 1 for 3 to 8+ cards\n", strings.Join(pays, "/"))
+	fmt.Fprintf(w, "Rounds:     %d\n", total.Rounds)
+	fmt.Fprintf(w, "Bust rate:  %.2f%%\n", total.BustRate()*100)
+	fmt.Fprintf(w, "Advantage:  %+.3f%% ± %.3f%%\n\n", edge*100, 1.96*stdErr*100)
+	fmt.Fprintf(w, "%5s %10s %7s %7s %20s\n", "TC", "Rounds", "Share", "Bust", "Advantage")
+	for _, b := range r.Buckets {
+		if b.Rounds == 0 {
+			continue
+		}
+		label := fmt.Sprintf("%+d", b.TrueCount)
+		switch b.TrueCount {
+		case minBustCount:
+			label = fmt.Sprintf("≤%+d", b.TrueCount)
+		case maxBustCount:
+			label = fmt.Sprintf("≥%+d", b.TrueCount)
+		}
+		edge, stdErr := b.Edge(r.Paytable)
+		fmt.Fprintf(w, "%5s %10d %6.2f%% %6.2f%% %+10.2f%% ± %5.2f%%\n", label, b.Rounds,
+			float64(b.Rounds)/float64(total.Rounds)*100, b.BustRate()*100, edge*100, 1.96*stdErr*100)
+	}
+}
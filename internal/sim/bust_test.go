@@ -0,0 +1,51 @@
+package sim
+
+import (
+	"testing"
+
+	"blackjack/internal/data"
+)
+
+func TestSimulateDealerBust(t *testing.T) {
+	cfg := Config{Rules: data.DefaultRules(), Rounds: 300000, Workers: 2, Seed: 5}
+	r, err := SimulateDealerBust(cfg, data.DefaultPaytable(data.SideDealerBust))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	total := r.Total()
+	if total.Rounds != cfg.Rounds || r.Main.Rounds != cfg.Rounds {
+		t.Fatalf("expected %d rounds, got %d side and %d main", cfg.Rounds, total.Rounds, r.Main.Rounds)
+	}
+	if rate := total.BustRate(); rate < 0.26 || rate > 0.31 {
+		t.Errorf("dealer bust rate %.4f is far from the usual 28%%", rate)
+	}
+	if edge, _ := total.Edge(r.Paytable); edge >= 0 || edge < -0.06 {
+		t.Errorf("Bust It edge %.4f should be a small house edge", edge)
+	}
+
+	var low, high BustBucket
+	for _, b := range r.Buckets {
+		switch {
+		case b.TrueCount <= -2:
+			low.merge(b)
+		case b.TrueCount >= 2:
+			high.merge(b)
+		}
+	}
+	// Small cards make the long busts that pay most, so the bet is worth
+	// more at low counts.
+	lowEdge, _ := low.Edge(r.Paytable)
+	highEdge, _ := high.Edge(r.Paytable)
+	if lowEdge <= highEdge {
+		t.Errorf("expected the bet to do better at low counts: %+.4f vs %+.4f", lowEdge, highEdge)
+	}
+}
+
+func TestBustBucketEdge(t *testing.T) {
+	b := BustBucket{Rounds: 10, Busts: [6]int{2, 1}}
+	// Two 3-card busts pay 1 each, one 4-card bust pays 2 and seven lose.
+	edge, _ := b.Edge(data.DefaultPaytable(data.SideDealerBust))
+	if want := (2.0 + 2 - 7) / 10; edge != want {
+		t.Fatalf("expected edge %v, got %v", want, edge)
+	}
+}
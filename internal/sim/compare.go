@@ -0,0 +1,249 @@
+package sim
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sync"
+
+	"blackjack/internal/data"
+)
+
+// DecisionDiff counts a situation where A and B play differently.
+type DecisionDiff struct {
+	Situation string
+	A, B      data.Action
+	Count     int
+	sumTC     float64
+}
+
+// MeanTrueCount is the average true count when the situation came up.
+func (d DecisionDiff) MeanTrueCount() float64 {
+	if d.Count == 0 {
+		return 0
+	}
+	return d.sumTC / float64(d.Count)
+}
+
+// Comparison is the result of two configs playing the same shoes.
+type Comparison struct {
+	A, B   Config
+	ResA   Result
+	ResB   Result
+	Shoes  int
+	Paired float64
+	// Unpaired is the standard error the difference would have if the two
+	// sides had been dealt independent shoes.
+	Unpaired float64
+	// Decisions counts A's decisions; Differences lists those B would have
+	// played differently with A's count, most common first.
+	Decisions   int
+	Differences []DecisionDiff
+}
+
+// Diff is A's edge minus B's.
+func (c Comparison) Diff() float64 {
+	return c.ResA.Edge() - c.ResB.Edge()
+}
+
+// ConfidenceInterval returns the paired 95% interval around Diff.
+func (c Comparison) ConfidenceInterval() (float64, float64) {
+	margin := 1.96 * c.Paired
+	return c.Diff() - margin, c.Diff() + margin
+}
+
+type diffKey struct {
+	total, pair, up int
+	soft            bool
+	a, b            data.Action
+}
+
+type shoeMoney struct {
+	netA, betA, netB, betB float64
+}
+
+// Compare plays shoes shoes with each config. Shoe i is shuffled with
+// a.Seed+i for both sides, so they see the same cards until their play
+// diverges, and the pairing is restored at every shuffle. Both sides use the
+// fast engine and a's worker count.
+func Compare(a, b Config, shoes int) (Comparison, error) {
+	for _, cfg := range []Config{a, b} {
+		if err := cfg.Rules.Validate(); err != nil {
+			return Comparison{}, err
+		}
+		if err := checkFast(cfg.Rules); err != nil {
+			return Comparison{}, err
+		}
+	}
+	if shoes <= 0 {
+		return Comparison{}, fmt.Errorf("number of shoes must be positive")
+	}
+	a, b = a.withDefaults(), b.withDefaults()
+
+	money := make([]shoeMoney, shoes)
+	resA := make([]Result, a.Workers)
+	resB := make([]Result, a.Workers)
+	decisions := make([]int, a.Workers)
+	diffs := make([]map[diffKey]*DecisionDiff, a.Workers)
+	var wg sync.WaitGroup
+	for worker := range a.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			diffs[worker] = map[diffKey]*DecisionDiff{}
+			observe := func(s data.Situation, up int, canSplit bool, tc float64, action data.Action) {
+				decisions[worker]++
+				other, _ := b.Strategy.RecommendSituation(s, up, canSplit, tc)
+				if other == action {
+					return
+				}
+				key := diffKey{total: s.Total, pair: s.Pair, up: up, soft: s.Soft, a: action, b: other}
+				d := diffs[worker][key]
+				if d == nil {
+					d = &DecisionDiff{Situation: situationLabel(s, up), A: action, B: other}
+					diffs[worker][key] = d
+				}
+				d.Count++
+				d.sumTC += tc
+			}
+			for shoe := worker; shoe < shoes; shoe += a.Workers {
+				seed := a.Seed + int64(shoe)
+				engineA := NewFastEngine(a, seed)
+				engineA.observe = observe
+				shoeA := engineA.playShoe()
+				shoeB := NewFastEngine(b, seed).playShoe()
+				money[shoe] = shoeMoney{netA: shoeA.Net, betA: shoeA.Wagered, netB: shoeB.Net, betB: shoeB.Wagered}
+				resA[worker].Merge(shoeA)
+				resB[worker].Merge(shoeB)
+			}
+		}()
+	}
+	wg.Wait()
+
+	c := Comparison{A: a, B: b, Shoes: shoes}
+	merged := map[diffKey]*DecisionDiff{}
+	for worker := range a.Workers {
+		c.ResA.Merge(resA[worker])
+		c.ResB.Merge(resB[worker])
+		c.Decisions += decisions[worker]
+		for key, d := range diffs[worker] {
+			if m := merged[key]; m != nil {
+				m.Count += d.Count
+				m.sumTC += d.sumTC
+			} else {
+				merged[key] = d
+			}
+		}
+	}
+	for _, d := range merged {
+		c.Differences = append(c.Differences, *d)
+	}
+	slices.SortFunc(c.Differences, func(x, y DecisionDiff) int {
+		return cmp.Or(y.Count-x.Count, cmp.Compare(x.Situation, y.Situation), int(x.A)-int(y.A))
+	})
+	c.Paired, c.Unpaired = pairedErrors(money, c.ResA.Edge(), c.ResB.Edge())
+	return c, nil
+}
+
+// pairedErrors returns the standard error of the difference in edge with
+// shoes as the paired samples, and the error it would have unpaired. Each
+// side's edge is a ratio of sums, so its per-shoe residual is
+// (net - edge*bet) / mean bet, as in Result.StdError.
+func pairedErrors(money []shoeMoney, edgeA, edgeB float64) (float64, float64) {
+	n := float64(len(money))
+	if n < 2 {
+		return 0, 0
+	}
+	var betA, betB float64
+	for _, m := range money {
+		betA += m.betA
+		betB += m.betB
+	}
+	meanA, meanB := betA/n, betB/n
+	if meanA == 0 || meanB == 0 {
+		return 0, 0
+	}
+	var sumA, sumB, sumD float64
+	for _, m := range money {
+		ra := (m.netA - edgeA*m.betA) / meanA
+		rb := (m.netB - edgeB*m.betB) / meanB
+		sumA += ra * ra
+		sumB += rb * rb
+		sumD += (ra - rb) * (ra - rb)
+	}
+	paired := math.Sqrt(sumD / (n - 1) / n)
+	unpaired := math.Sqrt((sumA + sumB) / (n - 1) / n)
+	return paired, unpaired
+}
+
+// situationLabel names a hand the way index plays are named, e.g. "16 v 10",
+// "soft 18 v 3" or "8,8 v A".
+func situationLabel(s data.Situation, up int) string {
+	upLabel := fmt.Sprint(up)
+	if up == 11 {
+		upLabel = "A"
+	}
+	switch {
+	case s.Pair == 11:
+		return "A,A v " + upLabel
+	case s.Pair > 0:
+		return fmt.Sprintf("%d,%d v %s", s.Pair, s.Pair, upLabel)
+	case s.Soft:
+		return fmt.Sprintf("soft %d v %s", s.Total, upLabel)
+	default:
+		return fmt.Sprintf("%d v %s", s.Total, upLabel)
+	}
+}
+
+// Report prints both sides, the paired difference and up to limit of the
+// most common decisions that differed.
+func (c Comparison) Report(w io.Writer, limit int) {
+	for _, side := range []struct {
+		name string
+		cfg  Config
+		res  Result
+	}{{"A", c.A, c.ResA}, {"B", c.B, c.ResB}} {
+		fmt.Fprintf(w, "%s: %s, %s, %s\n", side.name, side.cfg.Rules, strategyName(side.cfg), bettingName(side.cfg))
+		fmt.Fprintf(w, "   advantage %+.3f%% ± %.3f%% over %d rounds\n",
+			side.res.Edge()*100, 1.96*side.res.StdError()*100, side.res.Rounds)
+	}
+	low, high := c.ConfidenceInterval()
+	fmt.Fprintf(w, "\nShoes:              %d, dealt identically to both sides (seed %d)\n", c.Shoes, c.A.Seed)
+	fmt.Fprintf(w, "A − B:              %+.3f%% of initial bets\n", c.Diff()*100)
+	fmt.Fprintf(w, "95%% paired interval: %+.3f%% to %+.3f%%\n", low*100, high*100)
+	if c.Paired > 0 {
+		fmt.Fprintf(w, "Unpaired interval:  ± %.3f%% (pairing cuts the noise %.1f times)\n",
+			1.96*c.Unpaired*100, c.Unpaired/c.Paired)
+	}
+	switch {
+	case low > 0:
+		fmt.Fprintln(w, "Verdict:            A is better")
+	case high < 0:
+		fmt.Fprintln(w, "Verdict:            B is better")
+	default:
+		fmt.Fprintln(w, "Verdict:            no significant difference")
+	}
+
+	differed := 0
+	for _, d := range c.Differences {
+		differed += d.Count
+	}
+	share := 0.0
+	if c.Decisions > 0 {
+		share = float64(differed) / float64(c.Decisions)
+	}
+	fmt.Fprintf(w, "\nDecisions that differ: %d of A's %d (%.2f%%)\n", differed, c.Decisions, share*100)
+	if len(c.Differences) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%-14s %-10s %-10s %10s %8s\n", "Situation", "A plays", "B plays", "Times", "Mean TC")
+	for i, d := range c.Differences {
+		if limit > 0 && i == limit {
+			fmt.Fprintf(w, "… and %d more\n", len(c.Differences)-limit)
+			break
+		}
+		fmt.Fprintf(w, "%-14s %-10s %-10s %10d %+8.1f\n", d.Situation, d.A, d.B, d.Count, d.MeanTrueCount())
+	}
+}
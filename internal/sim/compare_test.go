@@ -0,0 +1,77 @@
+package sim
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"blackjack/internal/data"
+)
+
+func TestCompareIdenticalConfigs(t *testing.T) {
+	cfg := Config{Rules: data.DefaultRules(), Workers: 2, Seed: 11}
+	c, err := Compare(cfg, cfg, 200)
+	if err != nil {
+		t.Fatalf("unexpected comparison error: %v", err)
+	}
+	if c.ResA != c.ResB {
+		t.Fatalf("identical configs on identical shoes should match:\n%+v\n%+v", c.ResA, c.ResB)
+	}
+	if c.Diff() != 0 || c.Paired != 0 || len(c.Differences) != 0 {
+		t.Fatalf("expected no difference, got %+.4f ± %.4f with %d differing situations", c.Diff(), c.Paired, len(c.Differences))
+	}
+	if c.Unpaired == 0 {
+		t.Fatal("expected an unpaired error for independent shoes")
+	}
+}
+
+func TestCompareIndexPlays(t *testing.T) {
+	rules := data.DefaultRules()
+	table, err := data.DefaultIndexTable(data.HiLo)
+	if err != nil {
+		t.Fatalf("unexpected index table error: %v", err)
+	}
+	a := Config{Rules: rules, Workers: 2, Seed: 3}
+	b := a
+	b.Strategy = &data.Advisor{Rules: rules, Deviations: table}
+	c, err := Compare(a, b, 500)
+	if err != nil {
+		t.Fatalf("unexpected comparison error: %v", err)
+	}
+	if c.Paired >= c.Unpaired {
+		t.Errorf("pairing should reduce the error: paired %.5f, unpaired %.5f", c.Paired, c.Unpaired)
+	}
+	if len(c.Differences) == 0 {
+		t.Fatal("expected index plays to differ from basic strategy")
+	}
+	top := c.Differences[0]
+	if top.Situation != "16 v 10" || top.A != data.ActionHit || top.B != data.ActionStand || top.MeanTrueCount() < 0 {
+		t.Errorf("expected 16 v 10 to differ most often at positive counts, got %+v", top)
+	}
+
+	var out bytes.Buffer
+	c.Report(&out, 5)
+	for _, want := range []string{"A − B:", "paired interval", "16 v 10"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected report to include %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestSituationLabel(t *testing.T) {
+	tests := []struct {
+		s    data.Situation
+		up   int
+		want string
+	}{
+		{data.Situation{Total: 16}, 10, "16 v 10"},
+		{data.Situation{Total: 18, Soft: true}, 3, "soft 18 v 3"},
+		{data.Situation{Total: 16, Pair: 8}, 11, "8,8 v A"},
+		{data.Situation{Total: 12, Soft: true, Pair: 11}, 6, "A,A v 6"},
+	}
+	for _, test := range tests {
+		if got := situationLabel(test.s, test.up); got != test.want {
+			t.Errorf("situationLabel(%+v, %d) = %q, want %q", test.s, test.up, got, test.want)
+		}
+	}
+}
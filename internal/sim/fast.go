@@ -0,0 +1,401 @@
+package sim
+
+import (
+	"math"
+	"math/rand"
+
+	"blackjack/internal/data"
+)
+
+// fastHand tracks only what settlement and strategy need. Cards are stored
+// by blackjack value with aces as 1.
+type fastHand struct {
+	hard        int
+	aces        int
+	cards       int
+	first       uint8
+	second      uint8
+	bet         int
+	split       bool
+	doubled     bool
+	surrendered bool
+	stood       bool
+}
+
+func (h *fastHand) reset() {
+	*h = fastHand{}
+}
+
+func (h *fastHand) add(v uint8) {
+	switch h.cards {
+	case 0:
+		h.first = v
+	case 1:
+		h.second = v
+	}
+	h.cards++
+	h.hard += int(v)
+	if v == 1 {
+		h.aces++
+	}
+}
+
+func (h *fastHand) soft() bool {
+	return h.aces > 0 && h.hard+10 <= 21
+}
+
+func (h *fastHand) value() int {
+	if h.soft() {
+		return h.hard + 10
+	}
+	return h.hard
+}
+
+func (h *fastHand) busted() bool {
+	return h.hard > 21
+}
+
+func (h *fastHand) blackjack() bool {
+	return h.cards == 2 && !h.split && h.value() == 21
+}
+
+func (h *fastHand) situation() data.Situation {
+	s := data.Situation{
+		Total: h.value(),
+		Soft:  h.soft(),
+		Cards: h.cards,
+		Split: h.split,
+	}
+	if h.cards == 2 && h.first == h.second {
+		s.Pair = strategyValue(h.first)
+	}
+	return s
+}
+
+// strategyValue converts a stored card value to the scale data.Card.Value
+// uses, where an ace is 11.
+func strategyValue(v uint8) int {
+	if v == 1 {
+		return 11
+	}
+	return int(v)
+}
+
+// FastEngine plays the same rules as data.Game for a single seat, but keeps
+// every buffer between rounds so a round allocates nothing. The shoe is an
+// array of card values read through an index, and it is shuffled exactly as
+// data.Game.Seed shuffles, so both engines deal identical shoes for a seed.
+type FastEngine struct {
+	rules    data.Rules
+	strategy *data.Advisor
+	bets     *data.BetAdvisor
+	// progression, when set, sizes bets in place of bets.
+	progression data.Progression
+	bankroll    int
+	rng         *rand.Rand
+	// observe, when set, sees every strategy decision.
+	observe func(s data.Situation, upcard int, canSplit bool, trueCount float64, action data.Action)
+	// dealerDone, when set, sees the true count each round was bet at and
+	// the dealer's finished hand.
+	dealerDone func(trueCount float64, cards int, busted bool)
+
+	shoe    []uint8
+	pos     int
+	tags    [11]int
+	running int
+
+	hands      []fastHand
+	nHands     int
+	active     int
+	dealer     fastHand
+	holeHidden bool
+}
+
+func NewFastEngine(cfg Config, seed int64) *FastEngine {
+	cfg = cfg.withDefaults()
+	e := &FastEngine{
+		rules:    cfg.Rules,
+		strategy: cfg.Strategy,
+		bets:     cfg.Bets,
+		bankroll: cfg.Bankroll,
+		rng:      rand.New(rand.NewSource(seed)),
+		shoe:     make([]uint8, cfg.Rules.Decks*52),
+		hands:    make([]fastHand, cfg.Rules.MaxSplitHands),
+	}
+	if cfg.Progression != nil {
+		e.progression = cfg.Progression()
+	}
+	// Every built-in count system tags face cards like tens.
+	for v := 1; v <= 10; v++ {
+		e.tags[v] = cfg.System.Tag(data.Card{Rank: data.Rank(v)})
+	}
+	e.shuffle()
+	return e
+}
+
+// shuffle lays the shoe out in data.NewDeck order before shuffling so that
+// the permutation matches data.Game for the same random source.
+func (e *FastEngine) shuffle() {
+	i := 0
+	for range e.rules.Decks {
+		for range 4 {
+			for rank := data.Ace; rank <= data.King; rank++ {
+				e.shoe[i] = uint8(min(int(rank), 10))
+				i++
+			}
+		}
+	}
+	e.rng.Shuffle(len(e.shoe), func(i, j int) {
+		e.shoe[i], e.shoe[j] = e.shoe[j], e.shoe[i]
+	})
+	e.pos = 0
+	e.running = 0
+}
+
+func (e *FastEngine) drawHidden() uint8 {
+	if e.pos == len(e.shoe) {
+		e.shuffle()
+	}
+	v := e.shoe[e.pos]
+	e.pos++
+	return v
+}
+
+func (e *FastEngine) draw() uint8 {
+	v := e.drawHidden()
+	e.running += e.tags[v]
+	return v
+}
+
+func (e *FastEngine) trueCount() float64 {
+	unseen := len(e.shoe) - e.pos
+	if e.holeHidden && e.dealer.cards > 1 {
+		unseen++
+	}
+	return data.TrueCount(e.running, unseen)
+}
+
+func (e *FastEngine) needsShuffle() bool {
+	return float64(e.pos) >= e.rules.Penetration*float64(len(e.shoe))
+}
+
+// PlayRound plays one round with the configured strategy and bets, adds it
+// to res and returns the seat's net result in dollars.
+func (e *FastEngine) PlayRound(res *Result) float64 {
+	betCount := 0.0
+	if e.dealerDone != nil {
+		betCount = e.trueCount()
+	}
+	bet := e.rules.MinBet
+	switch {
+	case e.progression != nil:
+		bet = e.rules.CapBet(e.progression.Bet(), e.bankroll)
+	case e.bets != nil:
+		bet = e.bets.Suggest(e.trueCount(), e.bankroll)
+	}
+	bet = max(bet, 1)
+
+	e.dealer.reset()
+	e.holeHidden = true
+	e.nHands = 1
+	e.active = 0
+	e.hands[0].reset()
+	e.hands[0].bet = bet
+	staked := bet
+
+	e.hands[0].add(e.draw())
+	e.dealer.add(e.draw())
+	e.hands[0].add(e.draw())
+	e.dealer.add(e.drawHidden())
+	if e.dealer.blackjack() {
+		e.hands[0].stood = true
+	}
+
+	upcard := strategyValue(e.dealer.first)
+	for !e.readyForDealer() {
+		staked += e.playHand(upcard)
+	}
+
+	e.holeHidden = false
+	e.running += e.tags[e.dealer.second]
+	for e.dealerShouldHit() {
+		e.dealer.add(e.draw())
+	}
+	if e.dealerDone != nil {
+		e.dealerDone(betCount, e.dealer.cards, e.dealer.busted())
+	}
+
+	returned := 0
+	for i := range e.nHands {
+		hand := &e.hands[i]
+		outcome := e.outcome(hand)
+		returned += payout(hand.bet*halves, outcome)
+		res.addOutcome(outcome)
+	}
+	net := float64(returned-staked*halves) / halves
+	res.addMoney(bet, net)
+	if e.progression != nil {
+		e.progression.Record(int(math.Round(net)))
+	}
+
+	if e.needsShuffle() {
+		e.shuffle()
+	}
+	return net
+}
+
+// playShoe plays rounds until the cut card comes out and returns their
+// tally.
+func (e *FastEngine) playShoe() Result {
+	var res Result
+	for {
+		e.PlayRound(&res)
+		if e.pos == 0 {
+			return res
+		}
+	}
+}
+
+// playHand makes one decision for the active hand and returns any extra
+// money staked by doubling or splitting.
+func (e *FastEngine) playHand(upcard int) int {
+	hand := &e.hands[e.active]
+	if hand.blackjack() {
+		e.stand(hand)
+		return 0
+	}
+	canSplit := hand.cards == 2 && hand.first == hand.second && e.nHands < len(e.hands)
+	situation, trueCount := hand.situation(), e.trueCount()
+	action, _ := e.strategy.RecommendSituation(situation, upcard, canSplit, trueCount)
+	if e.observe != nil {
+		e.observe(situation, upcard, canSplit, trueCount, action)
+	}
+	switch action {
+	case data.ActionHit:
+		hand.add(e.draw())
+		if hand.busted() || e.charlie(hand) {
+			e.stand(hand)
+		}
+	case data.ActionDouble:
+		extra := hand.bet
+		hand.bet *= 2
+		hand.doubled = true
+		hand.add(e.draw())
+		e.stand(hand)
+		return extra
+	case data.ActionSplit:
+		return e.split()
+	case data.ActionSurrender:
+		hand.surrendered = true
+		e.stand(hand)
+	default:
+		e.stand(hand)
+	}
+	return 0
+}
+
+func (e *FastEngine) split() int {
+	copy(e.hands[e.active+2:e.nHands+1], e.hands[e.active+1:e.nHands])
+	e.nHands++
+	hand := &e.hands[e.active]
+	newHand := &e.hands[e.active+1]
+	card := hand.first
+	bet := hand.bet
+
+	hand.reset()
+	hand.bet = bet
+	hand.split = true
+	hand.add(card)
+	newHand.reset()
+	newHand.bet = bet
+	newHand.split = true
+	newHand.add(card)
+
+	hand.add(e.draw())
+	newHand.add(e.draw())
+	if card == 1 && !e.rules.HitSplitAces {
+		newHand.stood = true
+		e.stand(hand)
+	}
+	return bet
+}
+
+func (e *FastEngine) stand(hand *fastHand) {
+	hand.stood = true
+	for i := e.active + 1; i < e.nHands; i++ {
+		if !e.hands[i].stood && !e.hands[i].busted() {
+			e.active = i
+			return
+		}
+	}
+}
+
+// charlie reports whether hand holds enough cards to win outright.
+func (e *FastEngine) charlie(hand *fastHand) bool {
+	n := e.rules.CharlieCards()
+	return n > 0 && hand.cards >= n && !hand.busted()
+}
+
+func (e *FastEngine) readyForDealer() bool {
+	for i := range e.nHands {
+		if !e.hands[i].stood && !e.hands[i].busted() {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *FastEngine) dealerShouldHit() bool {
+	value := e.dealer.value()
+	if value < 17 {
+		return true
+	}
+	return value == 17 && e.rules.DealerHitsSoft17 && e.dealer.soft()
+}
+
+func (e *FastEngine) outcome(hand *fastHand) data.HandOutcome {
+	dealerBlackjack := e.dealer.blackjack()
+	switch {
+	case hand.busted():
+		return data.OutcomeLose
+	case hand.surrendered:
+		if dealerBlackjack {
+			return data.OutcomeLose
+		}
+		return data.OutcomeSurrender
+	case hand.blackjack() && !dealerBlackjack:
+		return data.OutcomeBlackjack
+	case e.charlie(hand) && !dealerBlackjack:
+		return data.OutcomeCharlie
+	case e.dealer.busted():
+		return data.OutcomeWin
+	case dealerBlackjack && !hand.blackjack():
+		return data.OutcomeLose
+	}
+	player, dealer := hand.value(), e.dealer.value()
+	switch {
+	case player > dealer:
+		return data.OutcomeWin
+	case player < dealer:
+		return data.OutcomeLose
+	default:
+		return data.OutcomePush
+	}
+}
+
+// payout mirrors data.Player.Payout. Bets are in half dollars, so it never
+// rounds.
+func payout(bet int, outcome data.HandOutcome) int {
+	switch outcome {
+	case data.OutcomePush:
+		return bet
+	case data.OutcomeWin, data.OutcomeCharlie:
+		return bet * 2
+	case data.OutcomeBlackjack:
+		return bet + (bet*3)/2
+	case data.OutcomeSurrender:
+		return bet / 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,117 @@
+package sim
+
+import (
+	"testing"
+
+	"blackjack/internal/data"
+)
+
+func TestFastEngineMatchesGame(t *testing.T) {
+	table, err := data.DefaultIndexTable(data.HiLo)
+	if err != nil {
+		t.Fatalf("unexpected index table error: %v", err)
+	}
+
+	s17 := data.DefaultRules()
+	s17.DealerHitsSoft17 = false
+	s17.Surrender = true
+	s17.Decks = 2
+	noDAS := data.DefaultRules()
+	noDAS.DoubleAfterSplit = false
+	noDAS.MaxSplitHands = 2
+	noDAS.Penetration = 0.9
+	tinyShoe := data.DefaultRules()
+	tinyShoe.Decks = 1
+	tinyShoe.Penetration = 1
+	charlie := data.DefaultRules()
+	charlie.Charlie = 5
+	hitAces := data.DefaultRules()
+	hitAces.HitSplitAces = true
+
+	cases := []struct {
+		name string
+		cfg  Config
+	}{
+		{"default rules", Config{Rules: data.DefaultRules()}},
+		{"s17 with surrender", Config{Rules: s17}},
+		{"no das, two hands", Config{Rules: noDAS}},
+		{"shoe runs dry", Config{Rules: tinyShoe}},
+		{"five-card charlie", Config{Rules: charlie}},
+		{"hit split aces", Config{Rules: hitAces}},
+		{"deviations and ramp", Config{
+			Rules:    data.DefaultRules(),
+			Strategy: &data.Advisor{Rules: data.DefaultRules(), Deviations: table},
+			Bets:     data.NewBetAdvisor(data.DefaultRules()),
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.cfg
+			cfg.Rounds = 30000
+			cfg.Workers = 2
+			cfg.Seed = 11
+			slow, err := Run(cfg)
+			if err != nil {
+				t.Fatalf("unexpected game engine error: %v", err)
+			}
+			cfg.Fast = true
+			fast, err := Run(cfg)
+			if err != nil {
+				t.Fatalf("unexpected fast engine error: %v", err)
+			}
+			if slow != fast {
+				t.Fatalf("engines disagree:\ngame: %+v\nfast: %+v", slow, fast)
+			}
+		})
+	}
+}
+
+func TestFastEngineRoundIsAllocationFree(t *testing.T) {
+	table, err := data.DefaultIndexTable(data.HiLo)
+	if err != nil {
+		t.Fatalf("unexpected index table error: %v", err)
+	}
+	rules := data.DefaultRules()
+	engine := NewFastEngine(Config{
+		Rules:    rules,
+		Strategy: &data.Advisor{Rules: rules, Deviations: table},
+		Bets:     data.NewBetAdvisor(rules),
+	}, 1)
+	var res Result
+	allocs := testing.AllocsPerRun(10000, func() {
+		engine.PlayRound(&res)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations per round, got %v", allocs)
+	}
+}
+
+func BenchmarkGameEngine(b *testing.B) {
+	cfg := Config{Rules: data.DefaultRules()}.withDefaults()
+	game, err := data.NewGameWithRules(cfg.Rules, []data.PlayerConfig{{Name: seatName, Bankroll: seatBankroll}})
+	if err != nil {
+		b.Fatalf("unexpected error creating game: %v", err)
+	}
+	game.Seed(1)
+	player := game.Players()[0]
+	var res Result
+	b.ResetTimer()
+	for range b.N {
+		if err := playRound(game, player, cfg, nil, &res); err != nil {
+			b.Fatalf("unexpected round error: %v", err)
+		}
+	}
+	b.ReportMetric(float64(res.Hands)/b.Elapsed().Seconds(), "hands/s")
+}
+
+func BenchmarkFastEngine(b *testing.B) {
+	engine := NewFastEngine(Config{Rules: data.DefaultRules()}, 1)
+	var res Result
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		engine.PlayRound(&res)
+	}
+	b.ReportMetric(float64(res.Hands)/b.Elapsed().Seconds(), "hands/s")
+}
@@ -0,0 +1,206 @@
+package sim
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"blackjack/internal/data"
+)
+
+// Result tallies a simulation. Money figures are in dollars; per-hand
+// statistics are expressed in units of the round's initial bet.
+type Result struct {
+	Rounds     int
+	Hands      int
+	Wins       int
+	Pushes     int
+	Losses     int
+	Surrenders int
+	Blackjacks int
+	Charlies   int
+	Wagered    float64
+	Net        float64
+
+	sumNetSq   float64
+	sumBetSq   float64
+	sumNetBet  float64
+	sumUnits   float64
+	sumUnitsSq float64
+}
+
+func (r *Result) addRound(bet int, net float64, results []data.RoundResult) {
+	r.addMoney(bet, net)
+	for _, res := range results {
+		r.addOutcome(res.Outcome)
+	}
+}
+
+func (r *Result) addMoney(bet int, n float64) {
+	b := float64(bet)
+	units := n / b
+	r.Rounds++
+	r.Wagered += b
+	r.Net += n
+	r.sumNetSq += n * n
+	r.sumBetSq += b * b
+	r.sumNetBet += n * b
+	r.sumUnits += units
+	r.sumUnitsSq += units * units
+}
+
+func (r *Result) addOutcome(outcome data.HandOutcome) {
+	r.Hands++
+	switch outcome {
+	case data.OutcomeWin:
+		r.Wins++
+	case data.OutcomeBlackjack:
+		r.Wins++
+		r.Blackjacks++
+	case data.OutcomeCharlie:
+		r.Wins++
+		r.Charlies++
+	case data.OutcomePush:
+		r.Pushes++
+	case data.OutcomeSurrender:
+		r.Surrenders++
+	default:
+		r.Losses++
+	}
+}
+
+func (r *Result) Merge(other Result) {
+	r.Rounds += other.Rounds
+	r.Hands += other.Hands
+	r.Wins += other.Wins
+	r.Pushes += other.Pushes
+	r.Losses += other.Losses
+	r.Surrenders += other.Surrenders
+	r.Blackjacks += other.Blackjacks
+	r.Charlies += other.Charlies
+	r.Wagered += other.Wagered
+	r.Net += other.Net
+	r.sumNetSq += other.sumNetSq
+	r.sumBetSq += other.sumBetSq
+	r.sumNetBet += other.sumNetBet
+	r.sumUnits += other.sumUnits
+	r.sumUnitsSq += other.sumUnitsSq
+}
+
+// Edge is the player's advantage: net result per dollar of initial bet.
+// It is negative when the house has the edge.
+func (r Result) Edge() float64 {
+	if r.Wagered == 0 {
+		return 0
+	}
+	return r.Net / r.Wagered
+}
+
+// StdDevPerHand is the standard deviation of one round's result measured in
+// initial bets.
+func (r Result) StdDevPerHand() float64 {
+	if r.Rounds < 2 {
+		return 0
+	}
+	n := float64(r.Rounds)
+	mean := r.sumUnits / n
+	variance := (r.sumUnitsSq - n*mean*mean) / (n - 1)
+	return math.Sqrt(math.Max(variance, 0))
+}
+
+// MeanNet is the average result of a round in dollars.
+func (r Result) MeanNet() float64 {
+	if r.Rounds == 0 {
+		return 0
+	}
+	return r.Net / float64(r.Rounds)
+}
+
+// StdDevPerRound is the standard deviation of one round's result in dollars.
+func (r Result) StdDevPerRound() float64 {
+	if r.Rounds < 2 {
+		return 0
+	}
+	n := float64(r.Rounds)
+	mean := r.MeanNet()
+	variance := (r.sumNetSq - n*mean*mean) / (n - 1)
+	return math.Sqrt(math.Max(variance, 0))
+}
+
+// StdError is the standard error of Edge. With a bet spread Edge is a ratio
+// of sums, so the error comes from the residuals net - edge*bet.
+func (r Result) StdError() float64 {
+	if r.Rounds < 2 || r.Wagered == 0 {
+		return 0
+	}
+	n := float64(r.Rounds)
+	e := r.Edge()
+	variance := (r.sumNetSq - 2*e*r.sumNetBet + e*e*r.sumBetSq) / (n - 1)
+	meanBet := r.Wagered / n
+	return math.Sqrt(math.Max(variance, 0)/n) / meanBet
+}
+
+// ConfidenceInterval returns the 95% confidence interval around Edge.
+func (r Result) ConfidenceInterval() (float64, float64) {
+	margin := 1.96 * r.StdError()
+	return r.Edge() - margin, r.Edge() + margin
+}
+
+func strategyName(cfg Config) string {
+	switch {
+	case cfg.Agent != nil:
+		if name, ok := cfg.Agent.(fmt.Stringer); ok {
+			return name.String()
+		}
+		return "custom agent"
+	case cfg.Strategy.Deviations != nil:
+		return fmt.Sprintf("basic strategy + %s index plays", cfg.Strategy.Deviations.System)
+	default:
+		return "basic strategy"
+	}
+}
+
+func (r Result) rate(count int) float64 {
+	if r.Hands == 0 {
+		return 0
+	}
+	return float64(count) / float64(r.Hands)
+}
+
+func (r Result) Report(w io.Writer, cfg Config) {
+	cfg = cfg.withDefaults()
+	strategy := strategyName(cfg)
+	betting := fmt.Sprintf("flat $%d", max(cfg.Rules.MinBet, 1))
+	if cfg.Bets != nil {
+		betting = fmt.Sprintf("%s on the %s count", cfg.Bets, cfg.System.Name)
+	}
+	if cfg.Agent != nil {
+		betting = "chosen by the agent"
+	}
+	if cfg.Progression != nil {
+		betting = cfg.Progression().String()
+	}
+	low, high := r.ConfidenceInterval()
+	blackjackRate := 0.0
+	if r.Rounds > 0 {
+		blackjackRate = float64(r.Blackjacks) / float64(r.Rounds)
+	}
+
+	fmt.Fprintf(w, "Rules:              %s, %.0f%% penetration\n", cfg.Rules, cfg.Rules.Penetration*100)
+	fmt.Fprintf(w, "Strategy:           %s\n", strategy)
+	fmt.Fprintf(w, "Betting:            %s\n", betting)
+	fmt.Fprintf(w, "Rounds:             %d (%d hands, %d workers, seed %d)\n", r.Rounds, r.Hands, cfg.Workers, cfg.Seed)
+	fmt.Fprintf(w, "Total wagered:      $%.0f initial, net $%+.0f\n", r.Wagered, r.Net)
+	fmt.Fprintf(w, "Player advantage:   %+.3f%% ± %.3f%%\n", r.Edge()*100, 1.96*r.StdError()*100)
+	fmt.Fprintf(w, "95%% interval:       %+.3f%% to %+.3f%%\n", low*100, high*100)
+	fmt.Fprintf(w, "House edge:         %.3f%%\n", -r.Edge()*100)
+	fmt.Fprintf(w, "Std dev per hand:   %.3f units\n", r.StdDevPerHand())
+	fmt.Fprintf(w, "Win / push / loss:  %.2f%% / %.2f%% / %.2f%%\n", r.rate(r.Wins)*100, r.rate(r.Pushes)*100, r.rate(r.Losses)*100)
+	if r.Surrenders > 0 {
+		fmt.Fprintf(w, "Surrendered:        %.2f%%\n", r.rate(r.Surrenders)*100)
+	}
+	fmt.Fprintf(w, "Blackjacks:         %.2f%% of rounds\n", blackjackRate*100)
+	if r.Charlies > 0 {
+		fmt.Fprintf(w, "Charlies:           %.2f%% of hands\n", r.rate(r.Charlies)*100)
+	}
+}
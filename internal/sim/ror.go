@@ -0,0 +1,179 @@
+package sim
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// Risk is a game's win rate and standard deviation per round in dollars,
+// the two inputs to the usual bankroll formulas. The formulas treat the
+// bankroll as a Brownian motion with that drift and volatility.
+type Risk struct {
+	EV     float64
+	StdDev float64
+}
+
+// Risk estimates the game's per-round win rate and deviation from a
+// simulation.
+func (r Result) Risk() Risk {
+	return Risk{EV: r.MeanNet(), StdDev: r.StdDevPerRound()}
+}
+
+func (r Risk) variance() float64 {
+	return r.StdDev * r.StdDev
+}
+
+// N0 is the number of rounds after which the expected win equals one
+// standard deviation.
+func (r Risk) N0() float64 {
+	if r.EV <= 0 {
+		return math.Inf(1)
+	}
+	return r.variance() / (r.EV * r.EV)
+}
+
+// RuinChance is the chance of ever losing bankroll when playing forever.
+func (r Risk) RuinChance(bankroll float64) float64 {
+	if r.EV <= 0 {
+		return 1
+	}
+	return math.Exp(-2 * r.EV * bankroll / r.variance())
+}
+
+// TripRuinChance is the chance of losing bankroll within rounds rounds.
+func (r Risk) TripRuinChance(bankroll float64, rounds int) float64 {
+	if rounds <= 0 {
+		return 0
+	}
+	t := float64(rounds)
+	spread := r.StdDev * math.Sqrt(t)
+	if spread == 0 {
+		if bankroll+r.EV*t <= 0 {
+			return 1
+		}
+		return 0
+	}
+	drift := r.EV * t
+	chance := normalCDF((-bankroll-drift)/spread) +
+		expTimesNormalCDF(-2*r.EV*bankroll/r.variance(), (-bankroll+drift)/spread)
+	return math.Min(math.Max(chance, 0), 1)
+}
+
+// BankrollFor is the bankroll that keeps the lifetime risk of ruin at ror.
+func (r Risk) BankrollFor(ror float64) float64 {
+	if r.EV <= 0 {
+		return math.Inf(1)
+	}
+	return -math.Log(ror) * r.variance() / (2 * r.EV)
+}
+
+// Hourly returns the expected win and its standard deviation over an hour
+// of roundsPerHour rounds.
+func (r Risk) Hourly(roundsPerHour float64) (float64, float64) {
+	return r.EV * roundsPerHour, r.StdDev * math.Sqrt(roundsPerHour)
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// expTimesNormalCDF computes exp(a)*Φ(x) without overflow when a is large
+// and x very negative, as happens for a losing game.
+func expTimesNormalCDF(a, x float64) float64 {
+	if x > -30 {
+		return math.Exp(a) * normalCDF(x)
+	}
+	// Φ(x) ≈ φ(x)/|x| far in the lower tail.
+	return math.Exp(a - x*x/2 - math.Log(-x*math.Sqrt(2*math.Pi)))
+}
+
+// RuinResult counts simulated bankrolls that went broke.
+type RuinResult struct {
+	Trials int
+	Ruined int
+}
+
+func (r RuinResult) Rate() float64 {
+	if r.Trials == 0 {
+		return 0
+	}
+	return float64(r.Ruined) / float64(r.Trials)
+}
+
+// StdError is the binomial standard error of Rate.
+func (r RuinResult) StdError() float64 {
+	if r.Trials == 0 {
+		return 0
+	}
+	p := r.Rate()
+	return math.Sqrt(p * (1 - p) / float64(r.Trials))
+}
+
+// SimulateRuin plays trials separate bankrolls of cfg.Bankroll dollars for
+// up to rounds rounds each with the fast engine, sizing bets from the
+// current bankroll, and counts those that can no longer cover the table
+// minimum. The result does not depend on the number of workers.
+func SimulateRuin(cfg Config, rounds, trials int) (RuinResult, error) {
+	if err := cfg.Rules.Validate(); err != nil {
+		return RuinResult{}, err
+	}
+	if err := checkFast(cfg.Rules); err != nil {
+		return RuinResult{}, err
+	}
+	if rounds <= 0 || trials <= 0 {
+		return RuinResult{}, fmt.Errorf("rounds and trials must be positive")
+	}
+	cfg = cfg.withDefaults()
+
+	result := RuinResult{Trials: trials}
+	for _, s := range playSessions(cfg, rounds, trials) {
+		if s.busted {
+			result.Ruined++
+		}
+	}
+	return result, nil
+}
+
+// RiskReport gathers a win-rate simulation and a ruin simulation for
+// printing side by side.
+type RiskReport struct {
+	Config        Config
+	Result        Result
+	Ruin          RuinResult
+	TripRounds    int
+	RoundsPerHour float64
+	TargetRoR     float64
+}
+
+func (r RiskReport) Report(w io.Writer) {
+	cfg := r.Config.withDefaults()
+	risk := r.Result.Risk()
+	bankroll := float64(cfg.Bankroll)
+	hourly, hourlySD := risk.Hourly(r.RoundsPerHour)
+	betting := fmt.Sprintf("flat $%d", max(cfg.Rules.MinBet, 1))
+	if cfg.Bets != nil {
+		betting = cfg.Bets.String()
+	}
+
+	fmt.Fprintf(w, "Rules:              %s, %.0f%% penetration\n", cfg.Rules, cfg.Rules.Penetration*100)
+	fmt.Fprintf(w, "Count:              %s\n", cfg.System.Name)
+	fmt.Fprintf(w, "Betting:            %s\n", betting)
+	fmt.Fprintf(w, "Bankroll:           $%d\n", cfg.Bankroll)
+	fmt.Fprintf(w, "Win rate:           $%+.3f per round (%+.3f%% of initial bets, %d rounds)\n",
+		risk.EV, r.Result.Edge()*100, r.Result.Rounds)
+	fmt.Fprintf(w, "Std dev:            $%.2f per round\n", risk.StdDev)
+	fmt.Fprintf(w, "Hourly:             $%+.2f ± $%.2f at %.0f rounds/hour\n", hourly, hourlySD, r.RoundsPerHour)
+	if risk.EV <= 0 {
+		fmt.Fprintln(w, "N0:                 never (the game has no edge)")
+		fmt.Fprintln(w, "Risk of ruin:       100% over a lifetime")
+	} else {
+		n0 := risk.N0()
+		fmt.Fprintf(w, "N0:                 %.0f rounds (%.0f hours)\n", n0, n0/r.RoundsPerHour)
+		fmt.Fprintf(w, "Risk of ruin:       %.2f%% over a lifetime\n", risk.RuinChance(bankroll)*100)
+		fmt.Fprintf(w, "Bankroll needed:    $%.0f for %.1f%% risk of ruin\n", risk.BankrollFor(r.TargetRoR), r.TargetRoR*100)
+	}
+	fmt.Fprintf(w, "Trip ruin:          %.2f%% by formula, %.2f%% ± %.2f%% simulated over %d rounds (%d trials)\n",
+		risk.TripRuinChance(bankroll, r.TripRounds)*100, r.Ruin.Rate()*100, 1.96*r.Ruin.StdError()*100,
+		r.TripRounds, r.Ruin.Trials)
+}
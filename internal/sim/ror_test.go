@@ -0,0 +1,65 @@
+package sim
+
+import (
+	"math"
+	"testing"
+
+	"blackjack/internal/data"
+)
+
+func TestRiskFormulas(t *testing.T) {
+	risk := Risk{EV: 1, StdDev: 10}
+	if got := risk.N0(); got != 100 {
+		t.Errorf("N0 = %v, want 100", got)
+	}
+	// exp(-2 * 1 * 100 / 100)
+	if got, want := risk.RuinChance(100), math.Exp(-2); math.Abs(got-want) > 1e-12 {
+		t.Errorf("RuinChance = %v, want %v", got, want)
+	}
+	if got := risk.RuinChance(risk.BankrollFor(0.05)); math.Abs(got-0.05) > 1e-12 {
+		t.Errorf("BankrollFor(0.05) gives a risk of %v", got)
+	}
+	if trip, life := risk.TripRuinChance(100, 1_000_000), risk.RuinChance(100); math.Abs(trip-life) > 1e-6 {
+		t.Errorf("a long trip should approach lifetime ruin: %v vs %v", trip, life)
+	}
+	if short, long := risk.TripRuinChance(100, 10), risk.TripRuinChance(100, 1000); short >= long {
+		t.Errorf("ruin should grow with trip length: %v then %v", short, long)
+	}
+
+	losing := Risk{EV: -1, StdDev: 10}
+	if got := losing.RuinChance(100); got != 1 {
+		t.Errorf("losing game lifetime ruin = %v, want 1", got)
+	}
+	if !math.IsInf(losing.N0(), 1) || !math.IsInf(losing.BankrollFor(0.05), 1) {
+		t.Error("a losing game should need unlimited rounds and bankroll")
+	}
+	if got := losing.TripRuinChance(1e6, 10); math.IsNaN(got) || got > 1e-6 {
+		t.Errorf("short trip with a huge bankroll should almost never ruin, got %v", got)
+	}
+}
+
+func TestSimulateRuin(t *testing.T) {
+	cfg := Config{Rules: data.DefaultRules(), Bankroll: 100, Workers: 3, Seed: 5}
+	first, err := SimulateRuin(cfg, 20000, 30)
+	if err != nil {
+		t.Fatalf("unexpected ruin simulation error: %v", err)
+	}
+	// Flat betting twenty units into a 0.4% house edge has about a 0.88
+	// lifetime risk of ruin, most of it within 20000 rounds.
+	if first.Rate() < 0.75 {
+		t.Errorf("expected most small bankrolls to go broke, got %.2f", first.Rate())
+	}
+
+	cfg.Workers = 1
+	second, err := SimulateRuin(cfg, 20000, 30)
+	if err != nil {
+		t.Fatalf("unexpected ruin simulation error: %v", err)
+	}
+	if first != second {
+		t.Errorf("ruin simulation depends on workers: %+v vs %+v", first, second)
+	}
+
+	if _, err := SimulateRuin(cfg, 0, 10); err == nil {
+		t.Error("expected an error for zero rounds")
+	}
+}
@@ -0,0 +1,170 @@
+package sim
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// session is one bankroll played until it runs out or the rounds are up.
+type session struct {
+	net    float64
+	busted bool
+	res    Result
+}
+
+// playSessions plays n sessions of up to rounds rounds each from
+// cfg.Bankroll with the fast engine. Session i shuffles with cfg.Seed+i, so
+// the sessions do not depend on the number of workers.
+func playSessions(cfg Config, rounds, n int) []session {
+	minimum := max(cfg.Rules.MinBet, 1)
+	sessions := make([]session, n)
+	var wg sync.WaitGroup
+	for worker := range cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := worker; i < n; i += cfg.Workers {
+				s := &sessions[i]
+				engine := NewFastEngine(cfg, cfg.Seed+int64(i))
+				bankroll := float64(cfg.Bankroll)
+				for range rounds {
+					if bankroll < float64(minimum) {
+						break
+					}
+					engine.bankroll = int(bankroll)
+					bankroll += engine.PlayRound(&s.res)
+				}
+				s.net = bankroll - float64(cfg.Bankroll)
+				s.busted = bankroll < float64(minimum)
+			}
+		}()
+	}
+	wg.Wait()
+	return sessions
+}
+
+// SessionResult is the spread of outcomes over many sessions.
+type SessionResult struct {
+	Name   string
+	Rounds int
+	// Nets holds each session's final win or loss in dollars, sorted.
+	Nets   []float64
+	Busted int
+	// Result merges every round of every session.
+	Result Result
+}
+
+// SimulateSessions plays sessions separate sessions of up to rounds rounds
+// from cfg.Bankroll, stopping a session early when it can no longer cover
+// the table minimum.
+func SimulateSessions(cfg Config, rounds, sessions int) (SessionResult, error) {
+	if err := cfg.Rules.Validate(); err != nil {
+		return SessionResult{}, err
+	}
+	if err := checkFast(cfg.Rules); err != nil {
+		return SessionResult{}, err
+	}
+	if rounds <= 0 || sessions <= 0 {
+		return SessionResult{}, fmt.Errorf("rounds and sessions must be positive")
+	}
+	cfg = cfg.withDefaults()
+
+	result := SessionResult{Name: bettingName(cfg), Rounds: rounds}
+	for _, s := range playSessions(cfg, rounds, sessions) {
+		result.Nets = append(result.Nets, s.net)
+		if s.busted {
+			result.Busted++
+		}
+		result.Result.Merge(s.res)
+	}
+	slices.Sort(result.Nets)
+	return result, nil
+}
+
+// Percentile returns the session net at fraction p of the way from the
+// worst session to the best.
+func (r SessionResult) Percentile(p float64) float64 {
+	if len(r.Nets) == 0 {
+		return 0
+	}
+	i := int(p * float64(len(r.Nets)-1))
+	return r.Nets[min(max(i, 0), len(r.Nets)-1)]
+}
+
+// Ahead is the share of sessions that finished with a profit.
+func (r SessionResult) Ahead() float64 {
+	ahead := len(r.Nets) - sortedCount(r.Nets, 0)
+	return r.share(ahead)
+}
+
+// BustRate is the share of sessions that lost the whole bankroll.
+func (r SessionResult) BustRate() float64 {
+	return r.share(r.Busted)
+}
+
+// MeanNet is the average session result in dollars.
+func (r SessionResult) MeanNet() float64 {
+	if len(r.Nets) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, net := range r.Nets {
+		total += net
+	}
+	return total / float64(len(r.Nets))
+}
+
+func (r SessionResult) share(n int) float64 {
+	if len(r.Nets) == 0 {
+		return 0
+	}
+	return float64(n) / float64(len(r.Nets))
+}
+
+// sortedCount counts the values in sorted that are at most limit.
+func sortedCount(sorted []float64, limit float64) int {
+	n, _ := slices.BinarySearchFunc(sorted, limit, func(net, limit float64) int {
+		if net <= limit {
+			return -1
+		}
+		return 1
+	})
+	return n
+}
+
+func bettingName(cfg Config) string {
+	switch {
+	case cfg.Progression != nil:
+		return cfg.Progression().String()
+	case cfg.Bets != nil:
+		return cfg.Bets.String()
+	default:
+		return fmt.Sprintf("flat $%d", max(cfg.Rules.MinBet, 1))
+	}
+}
+
+// ReportSessions prints one row per betting scheme. The edge column is the
+// result per dollar wagered, which no progression can change.
+func ReportSessions(w io.Writer, cfg Config, results []SessionResult) {
+	cfg = cfg.withDefaults()
+	if len(results) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Rules:     %s, %.0f%% penetration\n", cfg.Rules, cfg.Rules.Penetration*100)
+	fmt.Fprintf(w, "Sessions:  %d of up to %d rounds from a $%d bankroll\n\n",
+		len(results[0].Nets), results[0].Rounds, cfg.Bankroll)
+	fmt.Fprintf(w, "%-24s %8s %9s %8s %8s %8s %8s %8s %7s %7s\n",
+		"Betting", "Edge", "Avg bet", "Mean", "5%", "Median", "95%", "Best", "Ahead", "Bust")
+	for _, r := range results {
+		avgBet := 0.0
+		if r.Result.Rounds > 0 {
+			avgBet = r.Result.Wagered / float64(r.Result.Rounds)
+		}
+		fmt.Fprintf(w, "%-24s %+7.2f%% %9.2f %+8.0f %+8.0f %+8.0f %+8.0f %+8.0f %6.1f%% %6.1f%%\n",
+			r.Name, r.Result.Edge()*100, avgBet, r.MeanNet(),
+			r.Percentile(0.05), r.Percentile(0.5), r.Percentile(0.95), r.Percentile(1),
+			r.Ahead()*100, r.BustRate()*100)
+	}
+}
@@ -0,0 +1,83 @@
+package sim
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"blackjack/internal/data"
+)
+
+func progression(t *testing.T, name string) func() data.Progression {
+	t.Helper()
+	if _, err := data.NewProgression(name, 10); err != nil {
+		t.Fatalf("unexpected progression error: %v", err)
+	}
+	return func() data.Progression {
+		p, _ := data.NewProgression(name, 10)
+		return p
+	}
+}
+
+func TestSimulateSessions(t *testing.T) {
+	rules := data.DefaultRules()
+	rules.MinBet = 10
+	cfg := Config{Rules: rules, Bankroll: 1000, Workers: 3, Seed: 9}
+	flat, err := SimulateSessions(cfg, 50, 400)
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if len(flat.Nets) != 400 || !slices.IsSorted(flat.Nets) {
+		t.Fatalf("expected 400 sorted session results, got %d", len(flat.Nets))
+	}
+	if low, high := flat.Percentile(0), flat.Percentile(1); low < -1000 || high <= low {
+		t.Fatalf("implausible session range %.0f to %.0f", low, high)
+	}
+
+	cfg.Progression = progression(t, "martingale")
+	martingale, err := SimulateSessions(cfg, 50, 400)
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	// Martingale wins small most of the time and pays for it in busts.
+	if martingale.Ahead() <= flat.Ahead() {
+		t.Errorf("martingale ahead %.2f, flat ahead %.2f", martingale.Ahead(), flat.Ahead())
+	}
+	if martingale.BustRate() <= flat.BustRate() {
+		t.Errorf("martingale busts %.2f, flat busts %.2f", martingale.BustRate(), flat.BustRate())
+	}
+
+	cfg.Workers = 1
+	again, err := SimulateSessions(cfg, 50, 400)
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if !slices.Equal(again.Nets, martingale.Nets) {
+		t.Error("session results depend on the number of workers")
+	}
+
+	var out bytes.Buffer
+	ReportSessions(&out, cfg, []SessionResult{flat, martingale})
+	for _, want := range []string{"flat $10", "Martingale from $10"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected report to include %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestProgressionKeepsHouseEdge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("plays a million rounds per progression")
+	}
+	for _, name := range data.ProgressionNames() {
+		cfg := Config{Rules: data.DefaultRules(), Rounds: 1000000, Seed: 3, Fast: true, Progression: progression(t, name)}
+		res, err := Run(cfg)
+		if err != nil {
+			t.Fatalf("%s: unexpected simulation error: %v", name, err)
+		}
+		if edge := res.Edge(); edge < -0.02 || edge > 0.01 {
+			t.Errorf("%s: edge %.3f%% is far from the house edge", name, edge*100)
+		}
+	}
+}
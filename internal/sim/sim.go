@@ -0,0 +1,222 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+
+	"blackjack/internal/data"
+)
+
+const (
+	seatName = "Sim"
+	// The simulated seat never runs out of money; bet sizing uses
+	// Config.Bankroll instead.
+	seatBankroll = 1 << 50
+	// halves counts the table's money in half dollars, so a 3:2 blackjack
+	// or a surrender on an odd bet pays out exactly.
+	halves = 2
+)
+
+// inHalves returns rules with the table limits in half dollars.
+func inHalves(rules data.Rules) data.Rules {
+	rules.MinBet *= halves
+	rules.MaxBet *= halves
+	return rules
+}
+
+// Config describes a simulation run. Rounds are split evenly across
+// Workers and worker i shuffles with Seed+i, so a run is reproducible for a
+// given seed and worker count. Fast selects FastEngine, which deals the
+// same shoes and reaches the same results as data.Game; other variants
+// always run through data.Game. System is the count
+// that bets and index plays follow, Hi-Lo when unset. Agent, when set, bets
+// and plays the seat in place of Strategy and Bets; it always runs through
+// data.Game and must be safe for use by every worker at once. Progression,
+// when set, makes the bet progression that sizes every bet in place of Bets
+// or the agent; each worker and session gets a fresh one.
+type Config struct {
+	Rules    data.Rules
+	Rounds   int
+	Workers  int
+	Seed     int64
+	Strategy *data.Advisor
+	Bets     *data.BetAdvisor
+	Bankroll int
+	Fast     bool
+	System   data.CountSystem
+	Agent    data.Agent
+	// Progression bets are capped by the table limits and Bankroll.
+	Progression func() data.Progression
+}
+
+func (c Config) withDefaults() Config {
+	if c.Workers <= 0 {
+		c.Workers = runtime.GOMAXPROCS(0)
+	}
+	if c.Strategy == nil {
+		c.Strategy = data.NewAdvisor(c.Rules)
+	}
+	if c.Bankroll <= 0 {
+		c.Bankroll = 10000
+	}
+	if c.System.Name == "" {
+		c.System = data.HiLo
+	}
+	return c
+}
+
+// Run plays cfg.Rounds rounds through data.Game in parallel and merges the
+// per-worker tallies in worker order.
+func Run(cfg Config) (Result, error) {
+	if err := cfg.Rules.Validate(); err != nil {
+		return Result{}, err
+	}
+	if cfg.Rounds <= 0 {
+		return Result{}, fmt.Errorf("number of rounds must be positive")
+	}
+	cfg = cfg.withDefaults()
+
+	results := make([]Result, cfg.Workers)
+	errs := make([]error, cfg.Workers)
+	var wg sync.WaitGroup
+	for worker := range cfg.Workers {
+		rounds := cfg.Rounds / cfg.Workers
+		if worker < cfg.Rounds%cfg.Workers {
+			rounds++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[worker], errs[worker] = runWorker(cfg, cfg.Seed+int64(worker), rounds)
+		}()
+	}
+	wg.Wait()
+
+	var total Result
+	for worker, res := range results {
+		if errs[worker] != nil {
+			return Result{}, fmt.Errorf("worker %d: %w", worker, errs[worker])
+		}
+		total.Merge(res)
+	}
+	return total, nil
+}
+
+// checkFast refuses rules the fast engine cannot deal; it plays standard
+// blackjack only.
+func checkFast(rules data.Rules) error {
+	if rules.Variant != data.Standard {
+		return fmt.Errorf("the fast engine does not deal %s", rules.Variant)
+	}
+	if rules.NoHoleCard {
+		return fmt.Errorf("the fast engine always deals a hole card")
+	}
+	return nil
+}
+
+func runWorker(cfg Config, seed int64, rounds int) (Result, error) {
+	var res Result
+	if cfg.Fast && cfg.Agent == nil && checkFast(cfg.Rules) == nil {
+		engine := NewFastEngine(cfg, seed)
+		for range rounds {
+			engine.PlayRound(&res)
+		}
+		return res, nil
+	}
+	game, err := data.NewGameWithRules(inHalves(cfg.Rules), []data.PlayerConfig{{Name: seatName, Bankroll: seatBankroll, Agent: cfg.Agent}})
+	if err != nil {
+		return res, err
+	}
+	game.Seed(seed)
+	game.SetCountSystem(cfg.System)
+	player := game.Players()[0]
+	var progression data.Progression
+	if cfg.Progression != nil {
+		progression = cfg.Progression()
+	}
+	for range rounds {
+		if err := playRound(game, player, cfg, progression, &res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func playRound(game *data.Game, player *data.Player, cfg Config, progression data.Progression, res *Result) error {
+	bet := cfg.Rules.MinBet
+	switch {
+	case progression != nil:
+		bet = cfg.Rules.CapBet(progression.Bet(), cfg.Bankroll)
+	case cfg.Agent != nil:
+		view := game.View(player)
+		view.Rules = cfg.Rules
+		view.Bankroll = cfg.Bankroll
+		bet = cfg.Agent.Bet(view)
+	case cfg.Bets != nil:
+		bet = cfg.Bets.Suggest(game.TrueCount(), cfg.Bankroll)
+	}
+	bet = max(bet, 1)
+	before := player.Bankroll()
+
+	if err := game.StartRound(map[string]int{seatName: bet * halves}); err != nil {
+		return err
+	}
+	if err := game.DealInitialCards(); err != nil {
+		return err
+	}
+	if game.State() == data.StateSwitch {
+		hands := player.Hands()
+		if err := game.Switch(player, data.ShouldSwitch(hands[0], hands[1])); err != nil {
+			return err
+		}
+	}
+	if err := game.PlayAgents(); err != nil {
+		return err
+	}
+	dealer := game.Dealer().ActiveHand()
+	for !game.ReadyForDealer() {
+		if err := playHand(game, player, dealer, cfg); err != nil {
+			return err
+		}
+	}
+	if err := game.DealerPlay(); err != nil {
+		return err
+	}
+	results, err := game.SettleRound()
+	if err != nil {
+		return err
+	}
+	game.PrepareNextRound()
+
+	net := float64(player.Bankroll()-before) / halves
+	if progression != nil {
+		progression.Record(int(math.Round(net)))
+	}
+	res.addRound(bet*cfg.Rules.Boxes(), net, results)
+	return nil
+}
+
+func playHand(game *data.Game, player *data.Player, dealer *data.Hand, cfg Config) error {
+	hand := player.ActiveHand()
+	if hand.IsBlackjack() {
+		return game.Stand(player)
+	}
+	canSplit := hand.CanSplit() && len(player.Hands()) < cfg.Rules.MaxHands()
+	var err error
+	action, _ := cfg.Strategy.RecommendAgainst(hand, dealer, canSplit, game.TrueCount())
+	switch action {
+	case data.ActionHit:
+		_, err = game.Hit(player)
+	case data.ActionDouble:
+		_, err = game.DoubleDown(player)
+	case data.ActionSplit:
+		_, _, err = game.Split(player)
+	case data.ActionSurrender:
+		err = game.Surrender(player)
+	default:
+		err = game.Stand(player)
+	}
+	return err
+}
@@ -0,0 +1,162 @@
+package sim
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"blackjack/internal/data"
+)
+
+func TestRunIsDeterministic(t *testing.T) {
+	cfg := Config{Rules: data.DefaultRules(), Rounds: 20000, Workers: 3, Seed: 42}
+	first, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	second, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	if first != second {
+		t.Fatalf("expected identical results for identical seeds:\n%+v\n%+v", first, second)
+	}
+
+	cfg.Seed = 43
+	third, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	if third == first {
+		t.Fatal("expected a different seed to deal different shoes")
+	}
+}
+
+func TestRunTallies(t *testing.T) {
+	cfg := Config{Rules: data.DefaultRules(), Rounds: 50000, Workers: 2, Seed: 7}
+	res, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	if res.Rounds != cfg.Rounds {
+		t.Fatalf("expected %d rounds, got %d", cfg.Rounds, res.Rounds)
+	}
+	if res.Hands < res.Rounds {
+		t.Fatalf("expected at least one hand per round, got %d hands", res.Hands)
+	}
+	if res.Wins+res.Pushes+res.Losses+res.Surrenders != res.Hands {
+		t.Fatal("expected every hand to be tallied exactly once")
+	}
+	if edge := res.Edge(); edge < -0.05 || edge > 0.03 {
+		t.Fatalf("basic strategy edge %.3f%% is implausible", edge*100)
+	}
+	if sd := res.StdDevPerHand(); sd < 1 || sd > 1.3 {
+		t.Fatalf("standard deviation %.3f is implausible", sd)
+	}
+	low, high := res.ConfidenceInterval()
+	if !(low < res.Edge() && res.Edge() < high) {
+		t.Fatalf("expected edge inside its own interval, got %v < %v < %v", low, res.Edge(), high)
+	}
+
+	var out bytes.Buffer
+	res.Report(&out, cfg)
+	if !strings.Contains(out.String(), "House edge:") {
+		t.Fatalf("expected report to include house edge, got:\n%s", out.String())
+	}
+}
+
+func TestRunRejectsBadConfig(t *testing.T) {
+	if _, err := Run(Config{Rules: data.DefaultRules()}); err == nil {
+		t.Fatal("expected error for zero rounds")
+	}
+	rules := data.DefaultRules()
+	rules.Decks = 0
+	if _, err := Run(Config{Rules: rules, Rounds: 10}); err == nil {
+		t.Fatal("expected error for invalid rules")
+	}
+}
+
+func TestRunSwitchWithSurrender(t *testing.T) {
+	rules := data.DefaultRules()
+	rules.Variant = data.BlackjackSwitch
+	rules.Surrender = true
+	res, err := Run(Config{Rules: rules, Rounds: 20000, Workers: 2, Seed: 5})
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	if res.Surrenders == 0 {
+		t.Fatal("expected Switch boxes to surrender")
+	}
+}
+
+func TestEdgeDoesNotDependOnBetSize(t *testing.T) {
+	for _, fast := range []bool{false, true} {
+		var edges []float64
+		for _, minBet := range []int{5, 10} {
+			rules := data.DefaultRules()
+			rules.MinBet = minBet
+			rules.Surrender = true
+			res, err := Run(Config{Rules: rules, Rounds: 50000, Workers: 2, Seed: 13, Fast: fast})
+			if err != nil {
+				t.Fatalf("unexpected simulation error: %v", err)
+			}
+			edges = append(edges, res.Edge())
+		}
+		// The same seed deals the same rounds, so an odd bet's 3:2 blackjacks
+		// and surrenders are all that could move the edge.
+		if math.Abs(edges[0]-edges[1]) > 1e-12 {
+			t.Errorf("fast %v: $5 edge %.4f%%, $10 edge %.4f%%", fast, edges[0]*100, edges[1]*100)
+		}
+	}
+}
+
+func TestRunWithAgents(t *testing.T) {
+	rules := data.DefaultRules()
+	cfg := Config{Rules: rules, Rounds: 20000, Workers: 2, Seed: 5, Fast: true}
+	basic, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+
+	cfg.Agent = data.NewBasicBot(rules)
+	bot, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	if bot != basic {
+		t.Fatalf("expected the basic strategy bot to play like the built-in strategy:\n%+v\n%+v", bot, basic)
+	}
+
+	cfg.Agent = data.NeverBustBot{}
+	never, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	if never.Edge() >= bot.Edge() {
+		t.Fatalf("expected never-bust (%.3f%%) to lose to basic strategy (%.3f%%)", never.Edge()*100, bot.Edge()*100)
+	}
+
+	var out bytes.Buffer
+	never.Report(&out, cfg)
+	if !strings.Contains(out.String(), "never-bust bot") {
+		t.Fatalf("expected report to name the bot, got:\n%s", out.String())
+	}
+}
+
+func TestFreeBetEdgeMatchesPublished(t *testing.T) {
+	if testing.Short() {
+		t.Skip("plays two million rounds")
+	}
+	rules := data.DefaultRules()
+	rules.Variant = data.FreeBet
+	res, err := Run(Config{Rules: rules, Rounds: 2000000, Workers: 4, Seed: 10})
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	edge, _ := rules.EstimatedHouseEdge()
+	want := -edge
+	if low, high := res.ConfidenceInterval(); want < low || want > high {
+		t.Errorf("published edge %.3f%% outside the simulated %.3f%% to %.3f%%", want*100, low*100, high*100)
+	}
+}
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"blackjack/internal/data"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+const autoplaySpeedStep = 50 * time.Millisecond
+
+// AutoplayConfig controls the A key, which hands the seat to a strategy for
+// a run of rounds. Zero limits are ignored; Speed is the pause between
+// steps and zero plays as fast as the screen redraws.
+type AutoplayConfig struct {
+	// Agent plays the hands, and sizes the bets unless a progression is in
+	// use. Nil plays the hints and bets the suggested bet.
+	Agent  data.Agent
+	Rounds int
+	Speed  time.Duration
+	// Target stops once the bankroll reaches it.
+	Target int
+	// LossLimit stops once this much has been lost since autoplay started.
+	LossLimit int
+	// StopOnShuffle stops when the shoe is due to be reshuffled.
+	StopOnShuffle bool
+	// TrueCount stops once the true count reaches it, from below for a
+	// positive value and from above for a negative one.
+	TrueCount float64
+}
+
+func DefaultAutoplayConfig() AutoplayConfig {
+	return AutoplayConfig{Rounds: 100, Speed: 300 * time.Millisecond}
+}
+
+type autoplay struct {
+	cfg    AutoplayConfig
+	agent  data.Agent
+	on     bool
+	run    int
+	rounds int
+	start  int
+}
+
+type autoplayTickMsg struct {
+	run int
+}
+
+// UseAutoplay replaces the settings the A key starts autoplay with.
+func (m *Model) UseAutoplay(cfg AutoplayConfig) {
+	m.auto.cfg = cfg
+}
+
+func (m *Model) startAutoplay() tea.Cmd {
+	if m.player == nil {
+		return nil
+	}
+	m.auto.agent = m.auto.cfg.Agent
+	name := "the hints"
+	if m.auto.agent == nil {
+		m.auto.agent = &data.CounterBot{Advisor: m.advisor, Bets: m.bets, System: m.game.Counter().System()}
+	} else if stringer, ok := m.auto.agent.(fmt.Stringer); ok {
+		name = "the " + stringer.String()
+	} else {
+		name = "a custom agent"
+	}
+	m.auto.on = true
+	m.auto.run++
+	m.auto.rounds = 0
+	m.auto.start = m.player.Bankroll()
+	m.err = nil
+	m.log("Autoplay started with " + name)
+	return m.nextAutoplayTick()
+}
+
+func (m *Model) stopAutoplay(reason string) {
+	m.auto.on = false
+	m.auto.run++
+	m.log(fmt.Sprintf("Autoplay stopped after %d rounds: %s", m.auto.rounds, reason))
+}
+
+func (m *Model) nextAutoplayTick() tea.Cmd {
+	run := m.auto.run
+	if m.auto.cfg.Speed <= 0 {
+		return func() tea.Msg {
+			return autoplayTickMsg{run: run}
+		}
+	}
+	return tea.Tick(m.auto.cfg.Speed, func(time.Time) tea.Msg {
+		return autoplayTickMsg{run: run}
+	})
+}
+
+// autoplayStep takes one betting or playing step.
+func (m *Model) autoplayStep() tea.Cmd {
+	var err error
+	switch m.game.State() {
+	case data.StateBetting, data.StateSettled:
+		if reason := m.autoplayStopReason(); reason != "" {
+			m.stopAutoplay(reason)
+			return nil
+		}
+		bet := m.autoplayBet()
+		if bet <= 0 {
+			m.stopAutoplay(fmt.Sprintf("a $%d bankroll cannot cover the table minimum", m.player.Bankroll()))
+			return nil
+		}
+		m.auto.rounds++
+		err = m.handleCommand(strconv.Itoa(bet))
+	case data.StateSwitch:
+		hands := m.player.Hands()
+		err = m.handleCommand(switchChoice(data.ShouldSwitch(hands[0], hands[1])))
+	case data.StatePlayerAction:
+		err = m.handleCommand(m.autoplayAction().String())
+	}
+	if err != nil {
+		m.err = err
+		m.stopAutoplay("the last play failed")
+		return nil
+	}
+	m.err = nil
+	return m.nextAutoplayTick()
+}
+
+func (m *Model) autoplayStopReason() string {
+	cfg := m.auto.cfg
+	bankroll := m.player.Bankroll()
+	switch {
+	case cfg.Rounds > 0 && m.auto.rounds >= cfg.Rounds:
+		return fmt.Sprintf("played %d rounds", cfg.Rounds)
+	case m.auto.rounds == 0:
+		return ""
+	case cfg.Target > 0 && bankroll >= cfg.Target:
+		return fmt.Sprintf("bankroll reached $%d", cfg.Target)
+	case cfg.LossLimit > 0 && m.auto.start-bankroll >= cfg.LossLimit:
+		return fmt.Sprintf("lost the $%d limit", cfg.LossLimit)
+	case m.game.NeedsShuffle():
+		// The count is about to reset, so only a reshuffle stop applies.
+		if cfg.StopOnShuffle {
+			return "the shoe is due for a reshuffle"
+		}
+		return ""
+	}
+	tc := m.game.TrueCount()
+	if cfg.TrueCount > 0 && tc >= cfg.TrueCount || cfg.TrueCount < 0 && tc <= cfg.TrueCount {
+		return fmt.Sprintf("true count reached %+.1f", tc)
+	}
+	return ""
+}
+
+func (m *Model) autoplayBet() int {
+	if m.progression != nil {
+		return m.suggestedBet()
+	}
+	return m.game.Rules().CapBet(m.auto.agent.Bet(m.game.View(m.player)), m.boxBankroll())
+}
+
+// autoplayAction asks the agent for a play and hits instead of a double,
+// split or surrender that is not open, or a stand below the table's minimum,
+// as the game does for bot seats. A doubled hand waiting on a rescue stands
+// instead.
+func (m *Model) autoplayAction() data.Action {
+	hand := m.player.ActiveHand()
+	action := m.auto.agent.Play(m.game.View(m.player))
+	rules := m.game.Rules()
+	fallback := data.ActionHit
+	if hand != nil && hand.IsDoubleDown() && !rules.Buys() {
+		fallback = data.ActionStand
+	}
+	switch {
+	case action == data.ActionHit && fallback == data.ActionStand,
+		action == data.ActionStand && hand != nil && hand.Value() < rules.MinStick(),
+		action == data.ActionDouble && !canDouble(rules, m.player, hand),
+		action == data.ActionSplit && !canSplit(rules, m.player, hand),
+		action == data.ActionSurrender && !canSurrender(rules, m.player, hand):
+		return fallback
+	}
+	return action
+}
+
+func (m *Model) autoplayStatus() string {
+	speed := "instant"
+	if m.auto.cfg.Speed > 0 {
+		speed = m.auto.cfg.Speed.String()
+	}
+	rounds := strconv.Itoa(m.auto.rounds)
+	if m.auto.cfg.Rounds > 0 {
+		rounds += "/" + strconv.Itoa(m.auto.cfg.Rounds)
+	}
+	return fmt.Sprintf("   Autoplay: round %s, %s per step", rounds, speed)
+}
@@ -0,0 +1,433 @@
+package tui
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"blackjack/internal/data"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// DrillMode selects which counting exercise the drill screen runs.
+type DrillMode int
+
+const (
+	DrillCountdown DrillMode = iota
+	DrillFlash
+	DrillTrueCount
+)
+
+const (
+	minDrillSpeed   = 50 * time.Millisecond
+	drillSpeedStep  = 50 * time.Millisecond
+	maxDrillHistory = 5
+)
+
+var (
+	drillTrayFull  = lipgloss.NewStyle().Foreground(lipgloss.Color("#F97316"))
+	drillTrayEmpty = lipgloss.NewStyle().Foreground(lipgloss.Color("#4B5563"))
+	drillPassStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#34D399")).Bold(true)
+)
+
+// DrillConfig controls the pace and shape of a drill session. Seats is only
+// used by DrillFlash: zero flashes pairs, otherwise a full table layout of
+// that many two-card hands plus a dealer upcard is shown at once.
+type DrillConfig struct {
+	Mode      DrillMode
+	Decks     int
+	Speed     time.Duration
+	Seats     int
+	Questions int
+	System    data.CountSystem
+}
+
+type drillPhase int
+
+const (
+	drillDealing drillPhase = iota
+	drillAnswering
+	drillFinished
+)
+
+type drillResult struct {
+	mode     DrillMode
+	cards    int
+	elapsed  time.Duration
+	expected string
+	answer   string
+	correct  bool
+}
+
+type drillTickMsg struct {
+	run int
+}
+
+type DrillModel struct {
+	cfg      DrillConfig
+	deck     *data.Deck
+	shown    []data.Card
+	dealt    []data.Card
+	phase    drillPhase
+	run      int
+	input    string
+	started  time.Time
+	history  []drillResult
+	correct  int
+	attempts int
+	err      error
+	quitting bool
+	holdback int
+
+	// True count conversion state.
+	question  int
+	qCorrect  int
+	running   int
+	cardsLeft int
+}
+
+func NewDrill(cfg DrillConfig) *DrillModel {
+	if cfg.Decks <= 0 {
+		cfg.Decks = 1
+	}
+	if cfg.Speed < minDrillSpeed {
+		cfg.Speed = minDrillSpeed
+	}
+	if cfg.Questions <= 0 {
+		cfg.Questions = 10
+	}
+	if cfg.System.Name == "" {
+		cfg.System = data.HiLo
+	}
+	m := &DrillModel{cfg: cfg}
+	m.reset()
+	return m
+}
+
+func (m *DrillModel) Init() tea.Cmd {
+	return m.nextTick()
+}
+
+func (m *DrillModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case drillTickMsg:
+		if msg.run != m.run || m.phase != drillDealing {
+			return m, nil
+		}
+		return m, m.advance()
+	case tea.KeyPressMsg:
+		key := msg.Key()
+		text := strings.ToLower(key.Text)
+
+		if key.Mod&tea.ModCtrl != 0 && (key.Code == 'c' || key.Code == 'C') {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		if text == "q" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+
+		switch m.phase {
+		case drillDealing:
+			switch text {
+			case "+", "=":
+				m.cfg.Speed += drillSpeedStep
+			case "-":
+				if m.cfg.Speed-drillSpeedStep >= minDrillSpeed {
+					m.cfg.Speed -= drillSpeedStep
+				}
+			}
+		case drillAnswering:
+			switch key.Code {
+			case tea.KeyEnter:
+				if strings.TrimSpace(m.input) == "" {
+					break
+				}
+				if err := m.submit(); err != nil {
+					m.err = err
+				} else {
+					m.err = nil
+				}
+				m.input = ""
+			case tea.KeyBackspace, tea.KeyDelete:
+				m.input = trimLastRune(m.input)
+			default:
+				if key.Text != "" {
+					r, _ := utf8.DecodeRuneInString(key.Text)
+					if (r >= '0' && r <= '9') || (r == '-' && m.input == "") {
+						m.input += string(r)
+					}
+				}
+			}
+		case drillFinished:
+			if key.Code == tea.KeyEnter || text == "r" {
+				m.reset()
+				return m, m.nextTick()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *DrillModel) View() string {
+	if m.quitting {
+		return "Thanks for practicing!\n"
+	}
+
+	header := headerStyle.Render("♣ Count Drill — " + describeDrillMode(m.cfg))
+	info := infoStyle.Render(fmt.Sprintf("System: %s   Speed: %s   Accuracy: %s",
+		m.cfg.System.Name, m.cfg.Speed, m.accuracy()))
+
+	sections := []string{header, info}
+	if m.cfg.Mode == DrillTrueCount {
+		sections = append(sections, m.renderTrueCountQuestion())
+	} else {
+		sections = append(sections, m.renderShown())
+	}
+	if prompt := m.renderPrompt(); prompt != "" {
+		sections = append(sections, prompt)
+	}
+	if len(m.history) > 0 {
+		sections = append(sections, m.renderHistory())
+	}
+	if m.err != nil {
+		sections = append(sections, errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m *DrillModel) reset() {
+	m.run++
+	m.shown = nil
+	m.dealt = nil
+	m.input = ""
+	m.err = nil
+	m.started = time.Now()
+	m.question = 0
+	m.qCorrect = 0
+	if m.cfg.Mode == DrillTrueCount {
+		m.phase = drillAnswering
+		m.newTrueCountQuestion()
+		return
+	}
+	m.deck = data.NewDeck(m.cfg.Decks)
+	m.deck.Shuffle()
+	m.holdback = data.DrillHoldback(nil, m.deck.CardsLeft())
+	m.phase = drillDealing
+}
+
+func (m *DrillModel) nextTick() tea.Cmd {
+	if m.phase != drillDealing {
+		return nil
+	}
+	run := m.run
+	return tea.Tick(m.cfg.Speed, func(time.Time) tea.Msg {
+		return drillTickMsg{run: run}
+	})
+}
+
+func (m *DrillModel) advance() tea.Cmd {
+	size := m.groupSize()
+	if m.deck.CardsLeft()-m.holdback < size {
+		m.shown = nil
+		m.phase = drillAnswering
+		return nil
+	}
+	m.shown = m.shown[:0]
+	for range size {
+		card := m.deck.Deal()
+		m.shown = append(m.shown, card)
+		m.dealt = append(m.dealt, card)
+	}
+	return m.nextTick()
+}
+
+func (m *DrillModel) groupSize() int {
+	switch m.cfg.Mode {
+	case DrillFlash:
+		if m.cfg.Seats > 0 {
+			return m.cfg.Seats*2 + 1
+		}
+		return 2
+	default:
+		return 1
+	}
+}
+
+func (m *DrillModel) submit() error {
+	answer, err := strconv.Atoi(strings.TrimSpace(m.input))
+	if err != nil {
+		return fmt.Errorf("invalid count: %w", err)
+	}
+
+	if m.cfg.Mode == DrillTrueCount {
+		ok := data.CheckTrueCount(answer, m.running, m.cardsLeft)
+		if ok {
+			m.qCorrect++
+		}
+		m.question++
+		if m.question < m.cfg.Questions {
+			m.newTrueCountQuestion()
+			return nil
+		}
+		m.record(drillResult{
+			mode:     m.cfg.Mode,
+			cards:    m.cfg.Questions,
+			elapsed:  time.Since(m.started),
+			expected: fmt.Sprintf("%d/%d", m.cfg.Questions, m.cfg.Questions),
+			answer:   fmt.Sprintf("%d/%d", m.qCorrect, m.cfg.Questions),
+			correct:  m.qCorrect == m.cfg.Questions,
+		})
+		return nil
+	}
+
+	expected := m.cfg.System.RunningCount(m.dealt)
+	m.record(drillResult{
+		mode:     m.cfg.Mode,
+		cards:    len(m.dealt),
+		elapsed:  time.Since(m.started),
+		expected: strconv.Itoa(expected),
+		answer:   strconv.Itoa(answer),
+		correct:  answer == expected,
+	})
+	return nil
+}
+
+func (m *DrillModel) record(result drillResult) {
+	m.attempts++
+	if result.correct {
+		m.correct++
+	}
+	m.history = append(m.history, result)
+	if len(m.history) > maxDrillHistory {
+		m.history = m.history[len(m.history)-maxDrillHistory:]
+	}
+	m.phase = drillFinished
+}
+
+// newTrueCountQuestion picks a discard depth in half-deck steps and a running
+// count that is plausible for that depth.
+func (m *DrillModel) newTrueCountQuestion() {
+	halfDecks := m.cfg.Decks * 2
+	if halfDecks < 2 {
+		halfDecks = 2
+	}
+	discarded := rand.Intn(halfDecks-1) + 1
+	m.cardsLeft = (halfDecks - discarded) * 26
+	m.running = rand.Intn(25) - 12
+}
+
+func (m *DrillModel) accuracy() string {
+	if m.attempts == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%d/%d (%.0f%%)", m.correct, m.attempts, float64(m.correct)*100/float64(m.attempts))
+}
+
+func (m *DrillModel) renderShown() string {
+	if len(m.shown) == 0 {
+		switch m.phase {
+		case drillDealing:
+			return infoStyle.Render(fmt.Sprintf("Get ready… %d cards to go", m.deck.CardsLeft()-m.holdback))
+		default:
+			return infoStyle.Render(fmt.Sprintf("Dealt %d cards.", len(m.dealt)))
+		}
+	}
+
+	if m.cfg.Mode == DrillFlash && m.cfg.Seats > 0 {
+		dealer := lipgloss.JoinVertical(lipgloss.Left, sectionTitleStyle.Render("Dealer"), renderCard(m.shown[0]))
+		var seats []string
+		for i := 1; i+1 < len(m.shown); i += 2 {
+			seats = append(seats, handBoxStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top,
+				renderCard(m.shown[i]), renderCard(m.shown[i+1]))))
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, dealer, lipgloss.JoinHorizontal(lipgloss.Top, seats...))
+	}
+
+	var cards []string
+	for _, card := range m.shown {
+		cards = append(cards, renderCard(card))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, cards...)
+}
+
+func (m *DrillModel) renderTrueCountQuestion() string {
+	if m.phase == drillFinished {
+		return infoStyle.Render(fmt.Sprintf("Answered %d of %d correctly.", m.qCorrect, m.cfg.Questions))
+	}
+	total := m.cfg.Decks * 2
+	if total < 2 {
+		total = 2
+	}
+	discarded := total - m.cardsLeft/26
+	tray := drillTrayFull.Render(strings.Repeat("█", discarded)) +
+		drillTrayEmpty.Render(strings.Repeat("░", total-discarded))
+	lines := []string{
+		sectionTitleStyle.Render(fmt.Sprintf("Question %d of %d", m.question+1, m.cfg.Questions)),
+		fmt.Sprintf("Discard tray: %s  %.1f of %d decks played", tray, float64(discarded)/2, total/2),
+		valueStyle.Render(fmt.Sprintf("Running count: %+d", m.running)),
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (m *DrillModel) renderPrompt() string {
+	switch m.phase {
+	case drillDealing:
+		return promptStyle.Render("Keep the count. [+]/[-] change speed, [Q] quits.")
+	case drillAnswering:
+		label := "Running count (press Enter to check):"
+		if m.cfg.Mode == DrillTrueCount {
+			label = "True count (press Enter to check):"
+		}
+		return lipgloss.JoinVertical(lipgloss.Left,
+			promptStyle.Render(label),
+			inputStyle.Render(m.input))
+	case drillFinished:
+		last := m.history[len(m.history)-1]
+		verdict := errorStyle.Render("Missed")
+		if last.correct {
+			verdict = drillPassStyle.Render("Correct")
+		}
+		return lipgloss.JoinVertical(lipgloss.Left,
+			verdict+fmt.Sprintf(" — you said %s, answer %s, took %s", last.answer, last.expected, last.elapsed.Round(100*time.Millisecond)),
+			promptStyle.Render("Press Enter or R to drill again, Q to quit."))
+	default:
+		return ""
+	}
+}
+
+func (m *DrillModel) renderHistory() string {
+	lines := []string{"Recent drills:"}
+	for _, res := range m.history {
+		mark := "✗"
+		if res.correct {
+			mark = "✓"
+		}
+		unit := "cards"
+		if res.mode == DrillTrueCount {
+			unit = "questions"
+		}
+		lines = append(lines, fmt.Sprintf("  %s %d %s in %s (you %s, answer %s)",
+			mark, res.cards, unit, res.elapsed.Round(100*time.Millisecond), res.answer, res.expected))
+	}
+	return messageBoxStyle.Render(strings.Join(lines, "\n"))
+}
+
+func describeDrillMode(cfg DrillConfig) string {
+	switch cfg.Mode {
+	case DrillFlash:
+		if cfg.Seats > 0 {
+			return fmt.Sprintf("Flash (%d-seat table)", cfg.Seats)
+		}
+		return "Flash (pairs)"
+	case DrillTrueCount:
+		return "True Count Conversion"
+	default:
+		return fmt.Sprintf("Deck Countdown (%d deck)", cfg.Decks)
+	}
+}
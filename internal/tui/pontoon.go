@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"strings"
+
+	"blackjack/internal/data"
+)
+
+// playTerms names the plays and their hotkeys in the table's own language.
+type playTerms struct {
+	hitKey, hit       string
+	stand             string
+	doubleKey, double string
+	blackjack         string
+	prompt            string
+}
+
+var (
+	blackjackTerms = playTerms{hitKey: "H", hit: "Hit", stand: "Stand", doubleKey: "D", double: "Double", blackjack: "blackjack", prompt: "[H]it [S]tand [D]ouble"}
+	pontoonTerms   = playTerms{hitKey: "W", hit: "Twist", stand: "Stick", doubleKey: "B", double: "Buy", blackjack: "a pontoon", prompt: "T[W]ist [S]tick [B]uy"}
+)
+
+func termsFor(rules data.Rules) playTerms {
+	if rules.Variant == data.Pontoon {
+		return pontoonTerms
+	}
+	return blackjackTerms
+}
+
+// name gives the table's word for an action.
+func (t playTerms) name(action data.Action) string {
+	switch action {
+	case data.ActionHit:
+		return t.hit
+	case data.ActionStand:
+		return t.stand
+	case data.ActionDouble:
+		return t.double
+	default:
+		return action.String()
+	}
+}
+
+// command maps a hotkey to the play it makes, or "" for other keys.
+func (t playTerms) command(text string) string {
+	switch text {
+	case strings.ToLower(t.hitKey):
+		return "hit"
+	case "s":
+		return "stand"
+	case strings.ToLower(t.doubleKey):
+		return "double"
+	case "p":
+		return "split"
+	case "r":
+		return "surrender"
+	}
+	return ""
+}
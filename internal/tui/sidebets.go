@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+
+	"blackjack/internal/data"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+var focusedInputStyle = inputStyle.Copy().BorderForeground(lipgloss.Color("#F97316"))
+
+// sideBetSpots are the side-bet inputs Tab moves through after the main bet.
+var sideBetSpots = data.SideBetSpots()
+
+// focusedInput returns the betting input that typing goes to.
+func (m *Model) focusedInput() *string {
+	if m.focus == 0 {
+		return &m.input
+	}
+	return &m.sideInputs[m.focus-1]
+}
+
+func (m *Model) cycleFocus() {
+	m.focus = (m.focus + 1) % (len(sideBetSpots) + 1)
+}
+
+// sideBetStakes reads the side-bet inputs. They stay filled in between
+// rounds, so a side bet rides until it is cleared.
+func (m *Model) sideBetStakes() data.SideBets {
+	stakes := data.SideBets{}
+	for i, spot := range sideBetSpots {
+		if amount, err := strconv.Atoi(m.sideInputs[i]); err == nil && amount > 0 {
+			stakes[spot] = amount
+		}
+	}
+	return stakes
+}
+
+func (m *Model) renderBetInputs() string {
+	label := "Bet"
+	if m.game.Rules().Boxes() > 1 {
+		label = "Bet per box"
+	}
+	boxes := []string{m.renderBetInput(0, label, m.input)}
+	for i, spot := range sideBetSpots {
+		boxes = append(boxes, m.renderBetInput(i+1, spot.String(), m.sideInputs[i]))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, boxes...)
+}
+
+func (m *Model) renderBetInput(field int, label, value string) string {
+	style := inputStyle
+	if field == m.focus {
+		style = focusedInputStyle
+	}
+	return style.Render(fmt.Sprintf("%s $%s", label, value))
+}
+
+// describeSideBet reads like "Perfect Pairs $5: colored pair, wins $60".
+func describeSideBet(result data.SideBetResult) string {
+	text := fmt.Sprintf("%s $%d: %s, ", result.Bet, result.Amount, result.Outcome)
+	if result.Net > 0 {
+		return text + fmt.Sprintf("wins $%d", result.Net)
+	}
+	return text + fmt.Sprintf("loses $%d", result.Amount)
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"blackjack/internal/data"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// renderSwitchPreview shows the two boxes as dealt beside the same boxes
+// with their second cards switched, marking the arrangement the hints
+// prefer.
+func (m *Model) renderSwitchPreview() string {
+	hands := m.player.Hands()
+	first, second := data.SwitchedHands(hands[0], hands[1])
+	suggest := data.ShouldSwitch(hands[0], hands[1])
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		renderArrangement("As dealt [K]eep", hands[0], hands[1], !suggest),
+		"   ",
+		renderArrangement("Switched [W]", first, second, suggest))
+}
+
+func renderArrangement(title string, first, second *data.Hand, suggested bool) string {
+	if suggested {
+		title += " — suggested"
+	}
+	boxes := lipgloss.JoinHorizontal(lipgloss.Top,
+		renderPlayerHand(first, 0, suggested, ""),
+		renderPlayerHand(second, 1, suggested, ""))
+	return lipgloss.JoinVertical(lipgloss.Left, infoStyle.Render(title), boxes)
+}
+
+// chooseSwitch settles the switch phase for the player and starts play.
+func (m *Model) chooseSwitch(swap bool) error {
+	hands := m.player.Hands()
+	if m.training {
+		m.graded++
+		if swap == data.ShouldSwitch(hands[0], hands[1]) {
+			m.correctPlays++
+		} else {
+			m.log("Training: switch strategy says " + switchChoice(!swap) + ", not " + switchChoice(swap))
+		}
+	}
+	if err := m.game.Switch(m.player, swap); err != nil {
+		return err
+	}
+	if swap {
+		m.log("Switched the second cards")
+	} else {
+		m.log("Kept the cards as dealt")
+	}
+	return m.startPlay()
+}
+
+func switchChoice(swap bool) string {
+	if swap {
+		return "switch"
+	}
+	return "keep"
+}
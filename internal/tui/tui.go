@@ -3,10 +3,12 @@ package tui
 import (
 	"fmt"
 	"image/color"
+	"math"
 	"strconv"
 	"strings"
 	"unicode/utf8"
 
+	"blackjack/internal/analysis"
 	"blackjack/internal/data"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
@@ -33,14 +35,78 @@ var (
 )
 
 type Model struct {
-	game     *data.Game
-	player   *data.Player
-	input    string
-	messages []string
-	results  []data.RoundResult
-	prompt   string
-	err      error
-	quitting bool
+	game    *data.Game
+	player  *data.Player
+	advisor *data.Advisor
+	bets    *data.BetAdvisor
+	// progression, when set, sizes the suggested bet instead of bets.
+	progression data.Progression
+	// roundStart is the player's bankroll before the current round's bet.
+	roundStart int
+	input      string
+	// sideInputs hold the side-bet stakes in sideBetSpots order; focus is
+	// the betting input being typed into, 0 for the main bet.
+	sideInputs []string
+	focus      int
+	messages   []string
+	results    []data.RoundResult
+	prompt     string
+	err        error
+	quitting   bool
+
+	// Training mode grades every play against the advisor.
+	training     bool
+	graded       int
+	correctPlays int
+
+	// showEVs shows the exact EV of each action for the active hand. The
+	// EVs are cached until the hand changes.
+	showEVs bool
+	evs     evCache
+	// calc backs the exact overlays for the current round; its memo is
+	// dropped with it when the next round is dealt.
+	calc *analysis.Calculator
+	// showDealerBust shows the dealer's chance of busting under the upcard.
+	showDealerBust bool
+	dealerBust     overlayCache
+	// showOdds shows the active hand's bust and stand odds.
+	showOdds bool
+	odds     overlayCache
+	// reviewing shows the what-if review of the settled round.
+	reviewing bool
+	review    []analysis.ReviewStep
+	// auto hands the seat to a strategy while it runs.
+	auto autoplay
+}
+
+type evCache struct {
+	hand  *data.Hand
+	cards int
+	hands int
+	evs   []analysis.ActionEV
+}
+
+// overlayCache keeps an overlay's text until the unseen cards, the upcard
+// or the hand it describes change.
+type overlayCache struct {
+	shoe   analysis.Shoe
+	upcard data.Card
+	hand   *data.Hand
+	cards  int
+	text   string
+	set    bool
+}
+
+func (c *overlayCache) get(shoe analysis.Shoe, upcard data.Card, hand *data.Hand, compute func() string) string {
+	cards := 0
+	if hand != nil {
+		cards = len(hand.Cards())
+	}
+	if c.set && c.shoe == shoe && c.upcard == upcard && c.hand == hand && c.cards == cards {
+		return c.text
+	}
+	*c = overlayCache{shoe: shoe, upcard: upcard, hand: hand, cards: cards, text: compute(), set: true}
+	return c.text
 }
 
 func New(game *data.Game) *Model {
@@ -49,21 +115,51 @@ func New(game *data.Game) *Model {
 	if len(players) > 0 {
 		player = players[0]
 	}
+	advisor := data.NewAdvisor(game.Rules())
+	if table, err := data.DefaultIndexTable(game.Counter().System()); err == nil {
+		advisor.Deviations = table
+	}
 	m := &Model{
-		game:     game,
-		player:   player,
-		messages: []string{"Welcome to Blackjack. Place your opening bet."},
+		game:       game,
+		player:     player,
+		advisor:    advisor,
+		bets:       data.NewBetAdvisor(game.Rules()),
+		messages:   []string{"Welcome to Blackjack. Place your opening bet."},
+		sideInputs: make([]string, len(sideBetSpots)),
+		auto:       autoplay{cfg: DefaultAutoplayConfig()},
 	}
 	m.updatePrompt()
 	return m
 }
 
+// UseIndexTable replaces the index plays used for hints and training
+// grading. A nil table grades against plain basic strategy.
+func (m *Model) UseIndexTable(table *data.IndexTable) {
+	m.advisor.Deviations = table
+}
+
+// UseBetAdvisor replaces the advisor behind the suggested bet.
+func (m *Model) UseBetAdvisor(advisor *data.BetAdvisor) {
+	m.bets = advisor
+}
+
+// UseProgression sizes the suggested bet with a bet progression that follows
+// the player's results.
+func (m *Model) UseProgression(progression data.Progression) {
+	m.progression = progression
+}
+
 func (m *Model) Init() tea.Cmd {
 	return nil
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch keyMsg := msg.(type) {
+	case autoplayTickMsg:
+		if keyMsg.run != m.auto.run || !m.auto.on {
+			return m, nil
+		}
+		return m, m.autoplayStep()
 	case tea.KeyPressMsg:
 		key := keyMsg.Key()
 		text := strings.ToLower(key.Text)
@@ -78,6 +174,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+		if m.auto.on {
+			switch text {
+			case "a":
+				m.stopAutoplay("stopped by hand")
+			case "+", "=":
+				m.auto.cfg.Speed += autoplaySpeedStep
+			case "-":
+				m.auto.cfg.Speed = max(m.auto.cfg.Speed-autoplaySpeedStep, 0)
+			}
+			return m, nil
+		}
+
 		switch m.game.State() {
 		case data.StateBetting, data.StateSettled:
 			switch key.Code {
@@ -92,20 +200,59 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.err = nil
 				}
 				m.input = ""
+				m.focus = 0
+			case tea.KeyTab:
+				m.cycleFocus()
 			case tea.KeyBackspace, tea.KeyDelete:
-				m.input = trimLastRune(m.input)
+				input := m.focusedInput()
+				*input = trimLastRune(*input)
 			default:
 				if text == "?" {
 					m.showHelp()
 					break
 				}
+				if text == "t" {
+					m.toggleTraining()
+					break
+				}
+				if text == "b" {
+					m.acceptSuggestedBet()
+					break
+				}
+				if text == "a" {
+					return m, m.startAutoplay()
+				}
+				if text == "w" && m.game.State() == data.StateSettled {
+					m.toggleReview()
+					break
+				}
 				if key.Text != "" {
 					r, _ := utf8.DecodeRuneInString(key.Text)
 					if r >= '0' && r <= '9' {
-						m.input += string(r)
+						*m.focusedInput() += string(r)
 					}
 				}
 			}
+		case data.StateSwitch:
+			var command string
+			switch text {
+			case "?":
+				m.showHelp()
+			case "t":
+				m.toggleTraining()
+			case "w":
+				command = "switch"
+			case "k":
+				command = "keep"
+			}
+			if command == "" {
+				return m, nil
+			}
+			if err := m.handleCommand(command); err != nil {
+				m.err = err
+			} else {
+				m.err = nil
+			}
 		case data.StatePlayerAction:
 			if text == "?" {
 				m.showHelp()
@@ -114,15 +261,24 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			var command string
 			switch {
-			case text == "h":
-				command = "hit"
-			case text == "s" || key.Code == tea.KeyEnter:
+			case key.Code == tea.KeyEnter:
 				command = "stand"
-			case text == "d":
-				command = "double"
-			case text == "p":
-				command = "split"
+			case text == "t":
+				m.toggleTraining()
+				return m, nil
+			case text == "e":
+				m.showEVs = !m.showEVs
+				return m, nil
+			case text == "u":
+				m.showDealerBust = !m.showDealerBust
+				return m, nil
+			case text == "o":
+				m.showOdds = !m.showOdds
+				return m, nil
 			default:
+				command = termsFor(m.game.Rules()).command(text)
+			}
+			if command == "" {
 				return m, nil
 			}
 			if err := m.handleCommand(command); err != nil {
@@ -142,8 +298,16 @@ func (m *Model) View() string {
 		return "Thanks for playing!\n"
 	}
 
-	header := headerStyle.Render("♣ Blackjack")
-	info := infoStyle.Render(fmt.Sprintf("Deck cards remaining: %d", m.game.Deck().CardsLeft()))
+	header := headerStyle.Render("♣ Blackjack") + infoStyle.Render(fmt.Sprintf("  Progressive jackpot: $%.2f", m.game.Jackpot().Meter))
+	infoText := fmt.Sprintf("Deck cards remaining: %d", m.game.Deck().CardsLeft())
+	if m.training {
+		infoText += fmt.Sprintf("   Training: %d/%d   RC %+d   TC %+.1f",
+			m.correctPlays, m.graded, m.game.Counter().Running(), m.game.TrueCount())
+	}
+	if m.auto.on {
+		infoText += m.autoplayStatus()
+	}
+	info := infoStyle.Render(infoText)
 
 	dealerSection := m.renderDealerSection()
 	playerSection := m.renderPlayerSection()
@@ -151,13 +315,18 @@ func (m *Model) View() string {
 	prompt := m.renderPromptArea()
 
 	sections := []string{header, info, dealerSection, playerSection}
+	if others := m.renderOthers(); others != "" {
+		sections = append(sections, others)
+	}
 	if hotkeys != "" {
 		sections = append(sections, hotkeys)
 	}
 	if prompt != "" {
 		sections = append(sections, prompt)
 	}
-	if len(m.messages) > 0 {
+	if m.reviewing && m.game.State() == data.StateSettled {
+		sections = append(sections, m.renderReview())
+	} else if len(m.messages) > 0 {
 		sections = append(sections, m.renderMessages())
 	}
 	if m.err != nil {
@@ -170,6 +339,7 @@ func (m *Model) View() string {
 func (m *Model) handleCommand(cmd string) error {
 	switch m.game.State() {
 	case data.StateBetting, data.StateSettled:
+		shuffles := m.game.Shuffles()
 		if m.game.State() == data.StateSettled {
 			m.game.PrepareNextRound()
 		}
@@ -181,38 +351,67 @@ func (m *Model) handleCommand(cmd string) error {
 			return fmt.Errorf("bet must be positive")
 		}
 		bets := map[string]int{}
+		sideBets := map[string]data.SideBets{}
 		if m.player != nil {
 			bets[m.player.Name()] = amount
+			sideBets[m.player.Name()] = m.sideBetStakes()
+			m.roundStart = m.player.Bankroll()
 		}
-		if err := m.game.StartRound(bets); err != nil {
+		if err := m.game.StartRoundWithSideBets(bets, sideBets); err != nil {
 			return err
 		}
 		m.results = nil
 		m.messages = nil
-		m.log(fmt.Sprintf("Bet $%d", amount))
+		m.reviewing = false
+		m.review = nil
+		m.calc = nil
+		m.dealerBust, m.odds = overlayCache{}, overlayCache{}
+		if m.game.Shuffles() != shuffles {
+			m.log("Cut card reached: the shoe has been reshuffled")
+		}
+		if boxes := m.game.Rules().Boxes(); boxes > 1 {
+			m.log(fmt.Sprintf("Bet $%d on each of %d boxes", amount, boxes))
+		} else {
+			m.log(fmt.Sprintf("Bet $%d", amount))
+		}
 		if err := m.game.DealInitialCards(); err != nil {
 			return err
 		}
 		m.log("Cards dealt")
 		if m.player != nil {
-			hand := m.player.ActiveHand()
-			if hand.IsBlackjack() {
-				m.log("Blackjack!")
-				hand.Stand()
-				m.player.SetStatus(data.PlayerStatusStanding)
-				if m.game.ReadyForDealer() {
-					return m.completeRound()
+			for _, result := range m.player.SideBets() {
+				if result.Settled {
+					m.log(describeSideBet(result))
 				}
 			}
 		}
-		m.updatePrompt()
-		return nil
+		if m.game.State() == data.StateSwitch {
+			m.log("Keep the boxes as dealt or switch their second cards")
+			m.updatePrompt()
+			return nil
+		}
+		return m.startPlay()
+	case data.StateSwitch:
+		switch strings.ToLower(cmd) {
+		case "switch":
+			return m.chooseSwitch(true)
+		case "keep":
+			return m.chooseSwitch(false)
+		default:
+			return fmt.Errorf("unknown command: %s", cmd)
+		}
 	case data.StatePlayerAction:
 		if m.player == nil {
 			return fmt.Errorf("no player available")
 		}
 		hand := m.player.ActiveHand()
 		lower := strings.ToLower(cmd)
+		// The play is judged on the hand as it stands now but only counts
+		// once the game accepts it.
+		graded := func() {}
+		if m.training && hand != nil && !hand.IsStanding() {
+			graded = m.grade(lower, hand)
+		}
 		switch lower {
 		case "hit":
 			if hand == nil {
@@ -225,13 +424,12 @@ func (m *Model) handleCommand(cmd string) error {
 			if err != nil {
 				return err
 			}
-			m.log(fmt.Sprintf("Hit: drew %s", card.String()))
+			graded()
+			m.log(fmt.Sprintf("%s: drew %s", termsFor(m.game.Rules()).hit, card.String()))
 			if hand.IsBusted() {
 				m.log(fmt.Sprintf("Busted with %d", hand.Value()))
 			}
-			if m.game.ReadyForDealer() {
-				return m.completeRound()
-			}
+			return m.finishTurn()
 		case "stand":
 			if hand == nil {
 				return data.ErrNoActiveHand
@@ -242,10 +440,9 @@ func (m *Model) handleCommand(cmd string) error {
 			if err := m.game.Stand(m.player); err != nil {
 				return err
 			}
-			m.log("Stand")
-			if m.game.ReadyForDealer() {
-				return m.completeRound()
-			}
+			graded()
+			m.log(termsFor(m.game.Rules()).stand)
+			return m.finishTurn()
 		case "double":
 			if hand == nil {
 				return data.ErrNoActiveHand
@@ -253,46 +450,100 @@ func (m *Model) handleCommand(cmd string) error {
 			if hand.IsStanding() {
 				return fmt.Errorf("hand already standing")
 			}
-			if err := m.player.DoubleDownActiveHand(); err != nil {
-				return err
+			label := "Double down"
+			switch rules := m.game.Rules(); {
+			case rules.FreeDouble(hand):
+				label = "Free double"
+			case rules.Buys():
+				label = "Buy"
 			}
-			card, err := m.game.Hit(m.player)
+			card, err := m.game.DoubleDown(m.player)
 			if err != nil {
 				return err
 			}
-			m.log(fmt.Sprintf("Double down: drew %s", card.String()))
+			graded()
+			m.log(fmt.Sprintf("%s: drew %s", label, card.String()))
 			if hand.IsBusted() {
 				m.log(fmt.Sprintf("Busted with %d", hand.Value()))
 			}
-			if err := m.game.Stand(m.player); err != nil {
-				return err
-			}
-			if m.game.ReadyForDealer() {
-				return m.completeRound()
-			}
+			return m.finishTurn()
 		case "split":
 			if hand == nil {
 				return data.ErrNoActiveHand
 			}
-			newHand, err := m.player.SplitActiveHand()
+			label := "Split hand"
+			if m.game.Rules().FreeSplit(hand) {
+				label = "Free split"
+			}
+			firstCard, secondCard, err := m.game.Split(m.player)
 			if err != nil {
 				return err
 			}
-			firstCard := m.game.Deck().Deal()
-			hand.AddCard(firstCard)
-			secondCard := m.game.Deck().Deal()
-			newHand.AddCard(secondCard)
-			m.log(fmt.Sprintf("Split hand. Drew %s and %s", firstCard.String(), secondCard.String()))
+			graded()
+			m.log(fmt.Sprintf("%s. Drew %s and %s", label, firstCard.String(), secondCard.String()))
+			return m.finishTurn()
+		case "surrender":
+			if hand == nil {
+				return data.ErrNoActiveHand
+			}
+			rescue := hand.IsDoubleDown()
+			if err := m.game.Surrender(m.player); err != nil {
+				return err
+			}
+			graded()
+			if rescue {
+				m.log("Rescue: took back the double")
+			} else {
+				m.log("Surrender")
+			}
+			return m.finishTurn()
 		default:
 			return fmt.Errorf("unknown command: %s", cmd)
 		}
-		m.updatePrompt()
-		return nil
 	default:
 		return fmt.Errorf("game not ready for input")
 	}
 }
 
+// startPlay stands the player's blackjacks and reports the dealer's peek
+// once the cards are settled for play.
+func (m *Model) startPlay() error {
+	if m.player != nil {
+		for hand := m.player.ActiveHand(); hand != nil && hand.IsBlackjack() && !hand.IsStanding(); hand = m.player.ActiveHand() {
+			m.log("Blackjack!")
+			hand.Stand()
+			if !m.player.MoveToNextHand() {
+				break
+			}
+		}
+	}
+	if m.game.Dealer().ActiveHand().IsBlackjack() {
+		m.log("Dealer peeks: blackjack")
+	}
+	return m.finishTurn()
+}
+
+// finishTurn lets the bots play once every hand of the player is done, then
+// completes the round when the whole table is.
+func (m *Model) finishTurn() error {
+	if m.player != nil {
+		for _, hand := range m.player.Hands() {
+			if !hand.IsStanding() && !hand.IsBusted() {
+				m.updatePrompt()
+				return nil
+			}
+		}
+	}
+	if err := m.game.PlayAgents(); err != nil {
+		return err
+	}
+	if m.game.ReadyForDealer() {
+		return m.completeRound()
+	}
+	m.updatePrompt()
+	return nil
+}
+
 func (m *Model) completeRound() error {
 	if err := m.game.DealerPlay(); err != nil {
 		return err
@@ -302,9 +553,15 @@ func (m *Model) completeRound() error {
 		return err
 	}
 	m.results = results
+	if m.progression != nil && m.player != nil {
+		m.progression.Record(m.player.Bankroll() - m.roundStart)
+	}
 	m.messages = nil
 	for _, res := range results {
-		m.log(fmt.Sprintf("%s %s", res.Player.Name(), describeOutcome(res)))
+		m.log(fmt.Sprintf("%s %s", res.Player.Name(), describeOutcome(termsFor(m.game.Rules()), res)))
+	}
+	for _, hit := range m.game.JackpotHits() {
+		m.log("JACKPOT! " + hit.String())
 	}
 	m.updatePrompt()
 	return nil
@@ -317,10 +574,13 @@ func (m *Model) renderDealerSection() string {
 
 	var cards []string
 	if hand != nil && len(hand.Cards()) > 0 {
-		if dealer.HoleCardHidden() {
+		switch {
+		case dealer.UpcardHidden() && len(hand.Cards()) > 1:
+			cards = append(cards, renderFacedownCard(), renderFacedownCard())
+		case dealer.HoleCardHidden() && len(hand.Cards()) > 1:
 			cards = append(cards, renderCard(hand.Cards()[0]))
 			cards = append(cards, renderFacedownCard())
-		} else {
+		default:
 			for _, card := range hand.Cards() {
 				cards = append(cards, renderCard(card))
 			}
@@ -336,48 +596,239 @@ func (m *Model) renderDealerSection() string {
 
 	valueText := ""
 	if hand != nil {
-		if dealer.HoleCardHidden() {
+		if dealer.HoleCardHidden() && len(hand.Cards()) > 1 {
 			valueText = infoStyle.Render("Value: ??")
 		} else {
 			valueText = valueStyle.Render(fmt.Sprintf("Value: %d", hand.Value()))
 		}
 	}
+	if m.showDealerBust && m.game.State() == data.StatePlayerAction && len(cards) > 0 {
+		valueText += infoStyle.Render(m.dealerBustText())
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, title, body, valueText)
 }
 
+// dealerBustText gives the dealer's bust chance from the unseen cards once
+// the dealer has peeked.
+func (m *Model) dealerBustText() string {
+	calc := m.calculator()
+	if calc == nil {
+		return ""
+	}
+	shoe := analysis.ShoeFromRanks(m.game.UnseenRanks())
+	upcard := m.game.Dealer().ActiveHand().Cards()[0]
+	return m.dealerBust.get(shoe, upcard, nil, func() string {
+		probs := calc.DealerOutcomes(shoe, upcard, true)
+		return fmt.Sprintf("   Bust chance: %.1f%%", probs[analysis.DealerBust]*100)
+	})
+}
+
+// calculator returns the round's exact calculator, or nil when the rules
+// are beyond it.
+func (m *Model) calculator() *analysis.Calculator {
+	if m.calc == nil {
+		calc, err := analysis.NewCalculator(m.game.Rules())
+		if err != nil {
+			return nil
+		}
+		m.calc = calc
+	}
+	return m.calc
+}
+
 func (m *Model) renderPlayerSection() string {
 	if m.player == nil {
 		return ""
 	}
 
 	header := sectionTitleStyle.Render(fmt.Sprintf("%s — Bankroll: $%d", m.player.Name(), m.player.Bankroll()))
+	if state := m.game.State(); state == data.StateBetting || state == data.StateSettled {
+		if bet := m.suggestedBet(); bet > 0 {
+			header += infoStyle.Render(fmt.Sprintf("   Suggested bet: $%d (%s)", bet, m.betReason()))
+		}
+	}
+	if m.game.State() == data.StateSwitch {
+		return lipgloss.JoinVertical(lipgloss.Left, header, m.renderSwitchPreview())
+	}
 	var handViews []string
 	for i, hand := range m.player.Hands() {
 		active := m.game.State() == data.StatePlayerAction && i == m.player.ActiveHandIndex()
-		handViews = append(handViews, renderPlayerHand(hand, i, active))
+		odds := ""
+		if active && m.showOdds {
+			odds = m.oddsText(hand)
+		}
+		handViews = append(handViews, renderPlayerHand(hand, i, active, odds))
 	}
 	if len(handViews) == 0 {
 		handViews = append(handViews, infoStyle.Render("No cards yet"))
 	}
-	return lipgloss.JoinVertical(lipgloss.Left, header, lipgloss.JoinVertical(lipgloss.Left, handViews...))
+	sections := []string{header, lipgloss.JoinVertical(lipgloss.Left, handViews...)}
+	if m.showEVs && m.game.State() == data.StatePlayerAction {
+		sections = append(sections, m.renderActionEVs())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderOthers lists the other seats at the table, one line per hand.
+func (m *Model) renderOthers() string {
+	var lines []string
+	for _, player := range m.game.Players() {
+		if player == m.player {
+			continue
+		}
+		lines = append(lines, sectionTitleStyle.Render(fmt.Sprintf("%s — Bankroll: $%d", player.Name(), player.Bankroll())))
+		for _, hand := range player.Hands() {
+			if len(hand.Cards()) == 0 {
+				continue
+			}
+			var cards []string
+			for _, card := range hand.Cards() {
+				cards = append(cards, card.String())
+			}
+			text := fmt.Sprintf("  %s   Value: %d", strings.Join(cards, " "), hand.Value())
+			if hand.Bet() > 0 {
+				text += "   " + betText(hand)
+			}
+			if hand.IsBusted() {
+				text += tagStyle.Render("   BUST")
+			}
+			lines = append(lines, infoStyle.Render(text))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// oddsText describes the chance of busting on a hit and of winning by
+// standing now, from the cards the player has not seen.
+func (m *Model) oddsText(hand *data.Hand) string {
+	if hand.IsStanding() || hand.IsBusted() {
+		return ""
+	}
+	calc := m.calculator()
+	if calc == nil {
+		return ""
+	}
+	shoe := analysis.ShoeFromRanks(m.game.UnseenRanks())
+	upcard := m.game.Dealer().ActiveHand().Cards()[0]
+	return m.odds.get(shoe, upcard, hand, func() string {
+		odds := calc.StandOdds(shoe, upcard, hand.Value())
+		return fmt.Sprintf("Bust on hit: %.1f%%   Stand: win %.1f%% · push %.1f%%",
+			analysis.BustChance(hand, shoe)*100, odds.Win*100, odds.Push*100)
+	})
+}
+
+// actionEVs returns the EVs of the actions the player can take with the
+// active hand, computing them only when the hand has changed.
+func (m *Model) actionEVs() []analysis.ActionEV {
+	hand := m.player.ActiveHand()
+	dealerCards := m.game.Dealer().ActiveHand().Cards()
+	if hand == nil || len(dealerCards) == 0 {
+		return nil
+	}
+	hands := len(m.player.Hands())
+	if m.evs.hand == hand && m.evs.cards == len(hand.Cards()) && m.evs.hands == hands {
+		return m.evs.evs
+	}
+	calc := m.calculator()
+	if calc == nil {
+		return nil
+	}
+	shoe := analysis.ShoeFromRanks(m.game.UnseenRanks())
+	var evs []analysis.ActionEV
+	for _, ev := range calc.ActionEVs(hand, dealerCards[0], shoe, hands) {
+		// Leave out plays the bankroll cannot cover.
+		switch ev.Action {
+		case data.ActionDouble:
+			if !canDouble(m.game.Rules(), m.player, hand) {
+				continue
+			}
+		case data.ActionSplit:
+			if !canSplit(m.game.Rules(), m.player, hand) {
+				continue
+			}
+		}
+		evs = append(evs, ev)
+	}
+	m.evs = evCache{hand: hand, cards: len(hand.Cards()), hands: hands, evs: evs}
+	return evs
+}
+
+func (m *Model) renderActionEVs() string {
+	evs := m.actionEVs()
+	if len(evs) == 0 {
+		return ""
+	}
+	best := analysis.Best(evs)
+	parts := make([]string, 0, len(evs))
+	for _, ev := range evs {
+		part := fmt.Sprintf("%s %+.3f", ev.Action, ev.EV)
+		if ev.Action == best.Action {
+			part = valueStyle.Render(part)
+		}
+		parts = append(parts, part)
+	}
+	return infoStyle.Render("EV per unit: ") + strings.Join(parts, "   ")
 }
 
 func (m *Model) renderHotkeys() string {
+	if m.auto.on {
+		return hotkeyBarStyle.Render(renderHotkeyLine([]hotkey{
+			{Key: "A", Label: "Stop autoplay", Enabled: true},
+			{Key: "+/-", Label: "Slower/faster", Enabled: true},
+			{Key: "Q", Label: "Quit", Enabled: true},
+		}))
+	}
 	switch m.game.State() {
 	case data.StatePlayerAction:
 		hand := m.player.ActiveHand()
+		rules, terms := m.game.Rules(), termsFor(m.game.Rules())
+		doubleLabel, splitLabel := terms.double, "Split"
+		if hand != nil && m.game.Rules().FreeDouble(hand) {
+			doubleLabel = "Free double"
+		}
+		if hand != nil && m.game.Rules().FreeSplit(hand) {
+			splitLabel = "Free split"
+		}
 		hotkeys := []hotkey{
-			{Key: "H", Label: "Hit", Enabled: hand != nil && !hand.IsStanding() && !hand.IsBusted()},
-			{Key: "S", Label: "Stand", Enabled: hand != nil && !hand.IsStanding()},
-			{Key: "D", Label: "Double", Enabled: canDouble(m.player, hand)},
-			{Key: "P", Label: "Split", Enabled: canSplit(m.player, hand)},
+			{Key: terms.hitKey, Label: terms.hit, Enabled: hand != nil && !hand.IsStanding() && !hand.IsBusted() && (!hand.IsDoubleDown() || rules.Buys())},
+			{Key: "S", Label: terms.stand, Enabled: hand != nil && !hand.IsStanding() && hand.Value() >= rules.MinStick()},
+			{Key: terms.doubleKey, Label: doubleLabel, Enabled: canDouble(m.game.Rules(), m.player, hand)},
+			{Key: "P", Label: splitLabel, Enabled: canSplit(m.game.Rules(), m.player, hand)},
+		}
+		if rules.Surrender || rules.DoubleRescue() {
+			label := "Surrender"
+			if hand != nil && hand.IsDoubleDown() {
+				label = "Rescue"
+			}
+			hotkeys = append(hotkeys, hotkey{Key: "R", Label: label, Enabled: canSurrender(rules, m.player, hand)})
+		}
+		hotkeys = append(hotkeys, []hotkey{
+			{Key: "T", Label: toggleLabel("Training", m.training), Enabled: true},
+			{Key: "E", Label: toggleLabel("EVs", m.showEVs), Enabled: true},
+			{Key: "U", Label: toggleLabel("Dealer bust", m.showDealerBust), Enabled: true},
+			{Key: "O", Label: toggleLabel("Odds", m.showOdds), Enabled: true},
 			{Key: "?", Label: "Help", Enabled: true},
 			{Key: "Q", Label: "Quit", Enabled: true},
-		}
+		}...)
 		return hotkeyBarStyle.Render(renderHotkeyLine(hotkeys))
+	case data.StateSwitch:
+		return hotkeyBarStyle.Render(renderHotkeyLine([]hotkey{
+			{Key: "K", Label: "Keep", Enabled: true},
+			{Key: "W", Label: "Switch", Enabled: true},
+			{Key: "T", Label: toggleLabel("Training", m.training), Enabled: true},
+			{Key: "?", Label: "Help", Enabled: true},
+			{Key: "Q", Label: "Quit", Enabled: true},
+		}))
 	case data.StateBetting, data.StateSettled:
 		hotkeys := []hotkey{
+			{Key: "B", Label: "Bet suggestion", Enabled: m.suggestedBet() > 0},
+			{Key: "A", Label: "Autoplay", Enabled: m.player != nil},
+			{Key: "W", Label: toggleLabel("What if", m.reviewing), Enabled: m.game.State() == data.StateSettled && len(m.game.Decisions()) > 0},
+			{Key: "T", Label: toggleLabel("Training", m.training), Enabled: true},
 			{Key: "?", Label: "Help", Enabled: true},
 			{Key: "Q", Label: "Quit", Enabled: true},
 		}
@@ -391,14 +842,16 @@ func (m *Model) renderPromptArea() string {
 	switch m.game.State() {
 	case data.StateBetting:
 		return lipgloss.JoinVertical(lipgloss.Left,
-			promptStyle.Render("Bet amount (press Enter to confirm):"),
-			inputStyle.Render(fmt.Sprintf("$%s", m.input)))
+			promptStyle.Render("Bet amount (Tab moves to the side bets, Enter confirms):"),
+			m.renderBetInputs())
 	case data.StateSettled:
 		return lipgloss.JoinVertical(lipgloss.Left,
 			promptStyle.Render("Round settled. Enter next bet or press Q to quit."),
-			inputStyle.Render(fmt.Sprintf("$%s", m.input)))
+			m.renderBetInputs())
+	case data.StateSwitch:
+		return promptStyle.Render("Hotkeys: [K]eep [W] Switch [?]Help [Q]Quit")
 	case data.StatePlayerAction:
-		return promptStyle.Render("Hotkeys: [H]it [S]tand [D]ouble [P]Split [?]Help [Q]Quit")
+		return promptStyle.Render("Hotkeys: " + termsFor(m.game.Rules()).prompt + " [P]Split [?]Help [Q]Quit")
 	default:
 		return ""
 	}
@@ -409,7 +862,10 @@ func (m *Model) renderMessages() string {
 	if len(m.results) > 0 {
 		lines = append(lines, "Last round:")
 		for _, res := range m.results {
-			lines = append(lines, fmt.Sprintf("  %s", describeOutcome(res)))
+			lines = append(lines, fmt.Sprintf("  %s", describeOutcome(termsFor(m.game.Rules()), res)))
+			for _, side := range res.SideBets {
+				lines = append(lines, fmt.Sprintf("  %s", describeSideBet(side)))
+			}
 		}
 	}
 	if len(m.messages) > 0 {
@@ -425,6 +881,8 @@ func (m *Model) updatePrompt() {
 	switch m.game.State() {
 	case data.StateBetting:
 		m.prompt = "Enter bet amount"
+	case data.StateSwitch:
+		m.prompt = "Hotkeys: [K]eep [W] Switch"
 	case data.StatePlayerAction:
 		m.prompt = "Hotkeys: [H]it [S]tand [D]ouble [P]Split"
 	case data.StateSettled:
@@ -434,6 +892,148 @@ func (m *Model) updatePrompt() {
 	}
 }
 
+func (m *Model) suggestedBet() int {
+	switch {
+	case m.player == nil:
+		return 0
+	case m.progression != nil:
+		return m.game.Rules().CapBet(m.progression.Bet(), m.boxBankroll())
+	case m.bets != nil:
+		return m.bets.Suggest(m.game.TrueCount(), m.boxBankroll())
+	default:
+		return 0
+	}
+}
+
+// boxBankroll is the most the player can bet on each box.
+func (m *Model) boxBankroll() int {
+	return m.player.Bankroll() / m.game.Rules().Boxes()
+}
+
+// betReason explains where the suggested bet comes from.
+func (m *Model) betReason() string {
+	if m.progression != nil {
+		return m.progression.String()
+	}
+	return m.bets.Describe(m.game.TrueCount())
+}
+
+func (m *Model) acceptSuggestedBet() {
+	bet := m.suggestedBet()
+	if bet <= 0 {
+		m.err = fmt.Errorf("no bet to suggest with a $%d bankroll", m.player.Bankroll())
+		return
+	}
+	if err := m.handleCommand(strconv.Itoa(bet)); err != nil {
+		m.err = err
+	} else {
+		m.err = nil
+	}
+	m.input = ""
+	m.focus = 0
+}
+
+func (m *Model) toggleTraining() {
+	m.training = !m.training
+	if m.training {
+		m.log("Training mode on: plays are graded against the advisor.")
+	} else {
+		m.log(fmt.Sprintf("Training mode off. Final score %d/%d.", m.correctPlays, m.graded))
+	}
+}
+
+// grade judges command against the advised play for hand and returns the
+// function that records the verdict.
+func (m *Model) grade(command string, hand *data.Hand) func() {
+	dealerCards := m.game.Dealer().ActiveHand().Cards()
+	if len(dealerCards) == 0 {
+		return func() {}
+	}
+	trueCount := m.game.TrueCount()
+	splitAllowed := canSplit(m.game.Rules(), m.player, hand)
+	want, play := m.advisor.RecommendAgainst(hand, m.game.Dealer().ActiveHand(), splitAllowed, trueCount)
+	return func() {
+		m.graded++
+		if command == want.String() {
+			m.correctPlays++
+			if play != nil {
+				m.log(fmt.Sprintf("Training: correct — index play %s at TC %+.1f", play.Name, trueCount))
+			}
+			return
+		}
+		reason := "basic strategy"
+		if play != nil {
+			reason = fmt.Sprintf("index play %s at TC %+.1f", play.Name, trueCount)
+		}
+		terms := termsFor(m.game.Rules())
+		if action, err := data.ParseAction(command); err == nil {
+			command = strings.ToLower(terms.name(action))
+		}
+		m.log(fmt.Sprintf("Training: %s says %s, not %s", reason, strings.ToLower(terms.name(want)), command))
+	}
+}
+
+func (m *Model) toggleReview() {
+	if m.reviewing {
+		m.reviewing = false
+		return
+	}
+	if m.review == nil {
+		calc, err := analysis.NewCalculator(m.game.Rules())
+		if err != nil {
+			m.err = err
+			return
+		}
+		review, err := calc.Review(m.game, m.player)
+		if err != nil {
+			m.err = err
+			return
+		}
+		if len(review) == 0 {
+			m.err = fmt.Errorf("no decisions to review this round")
+			return
+		}
+		m.review = review
+	}
+	m.reviewing = true
+}
+
+func (m *Model) renderReview() string {
+	dealerCards := m.game.Dealer().ActiveHand().Cards()
+	lines := []string{"What if: replaying the cards that came next, then basic strategy"}
+	for _, step := range m.review {
+		decision := step.Decision
+		hand := decision.Hands[decision.Active]
+		var cards []string
+		for _, card := range hand.Cards {
+			cards = append(cards, card.String())
+		}
+		lines = append(lines, fmt.Sprintf("Hand %d: %s vs %s — you chose %s",
+			decision.Active+1, strings.Join(cards, " "), dealerCards[0], decision.Action))
+		best := step.Alternatives[0]
+		for _, alt := range step.Alternatives {
+			if alt.EV > best.EV {
+				best = alt
+			}
+		}
+		for _, alt := range step.Alternatives {
+			result := "    n/a"
+			if !math.IsNaN(alt.Result) {
+				result = fmt.Sprintf("%+7.2f", alt.Result)
+			}
+			line := fmt.Sprintf("  %-10s would have won %s   EV %+.3f", alt.Action, result, alt.EV)
+			if alt.Action == decision.Action {
+				line += "  ◀ chosen"
+			}
+			if alt.Action == best.Action {
+				line = valueStyle.Render(line + "  ★ best EV")
+			}
+			lines = append(lines, line)
+		}
+	}
+	return messageBoxStyle.Render(strings.Join(lines, "\n"))
+}
+
 func (m *Model) log(message string) {
 	if message == "" {
 		return
@@ -447,10 +1047,42 @@ func (m *Model) log(message string) {
 
 func (m *Model) showHelp() {
 	help := []string{
-		"Bet: type numbers then press Enter.",
-		"Hotkeys during play: H=Hit, S=Stand, D=Double, P=Split.",
+		"Bet: type numbers then press Enter, or press B to bet the suggestion.",
+		"Tab moves to the Perfect Pairs, 21+3, dealer bust and progressive side bets, which ride each round until cleared.",
+		"Hotkeys during play: H=Hit, S=Stand, D=Double, P=Split, R=Surrender.",
+		"T toggles training mode, which grades each play against basic strategy and index plays.",
+		"E shows the exact EV of each play for the cards left in the shoe.",
+		"U shows the dealer's chance of busting under the upcard.",
+		"O shows the active hand's chance of busting on a hit and of winning by standing.",
+		"W after a round reviews each decision against every alternative play.",
+		"A between rounds hands the seat to autoplay; A stops it and +/- change its speed.",
 		"Press ? anytime to show this help, Q to quit, Ctrl+C also exits.",
 	}
+	if rules := m.game.Rules(); rules.NoHoleCard {
+		text := "No hole card: the dealer draws a second card after everyone plays, and a blackjack then takes doubles and splits too."
+		if rules.OriginalBetsOnly {
+			text = "No hole card: the dealer draws a second card after everyone plays; a blackjack then takes only the original bet."
+		}
+		help = append(help, text)
+	}
+	if charlie := m.game.Rules().Charlie; charlie > 0 {
+		help = append(help, fmt.Sprintf("%d-card Charlie: a hand that reaches %d cards without busting stops drawing and wins even money unless the dealer has blackjack.", charlie, charlie))
+	}
+	if m.game.Rules().HoleCardExposed() {
+		help = append(help, "Double Exposure: both dealer cards are face up, ties lose unless both have blackjack, and blackjack pays even money.")
+	}
+	if m.game.Rules().Boxes() > 1 {
+		help = append(help, "Blackjack Switch: the bet goes on each of two boxes; after the deal K keeps them and W swaps their second cards.")
+	}
+	if m.game.Rules().Variant == data.Pontoon {
+		help = append(help, "Pontoon: both dealer cards are face down. W twists, S sticks on 15 or more, B buys a card for double the stake and leaves the hand open; five cards without busting make a trick, and pontoons and tricks pay 2:1 but the dealer wins every tie.")
+	}
+	if m.game.Rules().Variant == data.FreeBet {
+		help = append(help, "Free Bet: the house stakes doubles of hard 9-11 and splits of any pair but tens; free chips win but are not returned, and a dealer 22 pushes.")
+	}
+	if m.game.Rules().DoubleRescue() {
+		help = append(help, "Spanish 21: double on any number of cards; R after a double rescues the hand for half its bet.")
+	}
 	for _, line := range help {
 		m.log(line)
 	}
@@ -462,6 +1094,13 @@ type hotkey struct {
 	Enabled bool
 }
 
+func toggleLabel(name string, on bool) string {
+	if on {
+		return name + ": on"
+	}
+	return name + ": off"
+}
+
 func renderHotkeyLine(hotkeys []hotkey) string {
 	var parts []string
 	for _, hk := range hotkeys {
@@ -474,7 +1113,7 @@ func renderHotkeyLine(hotkeys []hotkey) string {
 	return strings.Join(parts, "  ")
 }
 
-func renderPlayerHand(hand *data.Hand, index int, active bool) string {
+func renderPlayerHand(hand *data.Hand, index int, active bool, odds string) string {
 	if hand == nil {
 		return ""
 	}
@@ -502,6 +1141,9 @@ func renderPlayerHand(hand *data.Hand, index int, active bool) string {
 	if hand.IsDoubleDown() {
 		tags = append(tags, "DOUBLE")
 	}
+	if hand.IsSurrendered() {
+		tags = append(tags, "SURRENDER")
+	}
 	if hand.IsStanding() {
 		tags = append(tags, "STAND")
 	}
@@ -512,16 +1154,20 @@ func renderPlayerHand(hand *data.Hand, index int, active bool) string {
 	}
 
 	info := fmt.Sprintf("Value: %d", hand.Value())
-	if bet := hand.Bet(); bet > 0 {
-		info += fmt.Sprintf("   Bet: $%d", bet)
+	if hand.Bet() > 0 {
+		info += "   " + betText(hand)
 	}
 
-	box := lipgloss.JoinVertical(lipgloss.Left,
+	lines := []string{
 		sectionTitleStyle.Render(title),
 		cardRow,
 		valueStyle.Render(info),
 		tagText,
-	)
+	}
+	if odds != "" {
+		lines = append(lines, infoStyle.Render(odds))
+	}
+	box := lipgloss.JoinVertical(lipgloss.Left, lines...)
 
 	if active {
 		return activeHandStyle.Render(box)
@@ -565,37 +1211,64 @@ func suitColor(suit data.Suit) color.Color {
 	}
 }
 
-func canDouble(player *data.Player, hand *data.Hand) bool {
+func canDouble(rules data.Rules, player *data.Player, hand *data.Hand) bool {
 	if player == nil || hand == nil {
 		return false
 	}
 	if hand.IsStanding() || hand.IsBusted() || hand.IsDoubleDown() {
 		return false
 	}
-	if len(hand.Cards()) != 2 {
+	if cards := len(hand.Cards()); cards < 2 || cards > 2 && !rules.LateDouble() {
+		return false
+	}
+	if hand.IsSplit() && !rules.DoubleAfterSplit {
 		return false
 	}
 	bet := hand.Bet()
 	if bet == 0 {
 		return false
 	}
-	return player.Bankroll() >= bet
+	return rules.FreeDouble(hand) || player.Bankroll() >= bet
 }
 
-func canSplit(player *data.Player, hand *data.Hand) bool {
+func canSplit(rules data.Rules, player *data.Player, hand *data.Hand) bool {
 	if player == nil || hand == nil {
 		return false
 	}
 	if hand.IsStanding() || hand.IsBusted() {
 		return false
 	}
-	if !hand.CanSplit() {
+	if !hand.CanSplit() || len(player.Hands()) >= rules.MaxHands() {
 		return false
 	}
-	return player.Bankroll() >= hand.Bet()
+	return rules.FreeSplit(hand) || player.Bankroll() >= hand.Bet()
 }
 
-func describeOutcome(res data.RoundResult) string {
+// betText shows a hand's bet, marking the chips the house put up for free
+// doubles and splits.
+func betText(hand *data.Hand) string {
+	free := hand.FreeBet()
+	if free == 0 {
+		return fmt.Sprintf("Bet: $%d", hand.Bet())
+	}
+	return fmt.Sprintf("Bet: $%d + $%d free", hand.Bet()-free, free)
+}
+
+// canSurrender also covers rescuing a doubled hand where the rules allow it.
+func canSurrender(rules data.Rules, player *data.Player, hand *data.Hand) bool {
+	if player == nil || hand == nil {
+		return false
+	}
+	if hand.IsDoubleDown() {
+		return rules.DoubleRescue() && !hand.IsStanding()
+	}
+	if !rules.Surrender || hand.IsStanding() || hand.IsSplit() {
+		return false
+	}
+	return len(hand.Cards()) == 2
+}
+
+func describeOutcome(terms playTerms, res data.RoundResult) string {
 	hand := res.Hand
 	value := 0
 	if hand != nil {
@@ -603,11 +1276,23 @@ func describeOutcome(res data.RoundResult) string {
 	}
 	switch res.Outcome {
 	case data.OutcomeWin:
+		if res.Bonus != data.NoBonus {
+			num, den := res.Bonus.Pays()
+			return fmt.Sprintf("wins with a %s, paid %d:%d", res.Bonus, num, den)
+		}
 		return fmt.Sprintf("wins with %d", value)
+	case data.OutcomeCharlie:
+		if res.Bonus != data.NoBonus {
+			num, den := res.Bonus.Pays()
+			return fmt.Sprintf("wins with a %s, paid %d:%d", res.Bonus, num, den)
+		}
+		return fmt.Sprintf("wins with a %d-card Charlie", len(hand.Cards()))
 	case data.OutcomeBlackjack:
-		return "wins with blackjack"
+		return "wins with " + terms.blackjack
 	case data.OutcomePush:
 		return fmt.Sprintf("push with %d", value)
+	case data.OutcomeSurrender:
+		return fmt.Sprintf("surrenders with %d", value)
 	default:
 		return fmt.Sprintf("loses with %d", value)
 	}
@@ -1,21 +1,681 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 
+	"blackjack/internal/analysis"
 	"blackjack/internal/data"
+	"blackjack/internal/sim"
 	"blackjack/internal/tui"
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
 func main() {
-	game, err := data.NewGame(6, []data.PlayerConfig{{Name: "You", Bankroll: 500}})
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "drill":
+			runDrill(os.Args[2:])
+			return
+		case "indices":
+			runIndices(os.Args[2:])
+			return
+		case "sim":
+			runSim(os.Args[2:])
+			return
+		case "edge":
+			runEdge(os.Args[2:])
+			return
+		case "dealer":
+			runDealer(os.Args[2:])
+			return
+		case "ror":
+			runRor(os.Args[2:])
+			return
+		case "sessions":
+			runSessions(os.Args[2:])
+			return
+		case "compare":
+			runCompare(os.Args[2:])
+			return
+		case "bust":
+			runBust(os.Args[2:])
+			return
+		}
+	}
+
+	indices := flag.String("indices", "", "JSON index table to use instead of the built-in Illustrious 18 and Fab 4")
+	deviations := flag.Bool("deviations", true, "apply count-based index plays to hints and training grading")
+	bets := betFlags(flag.CommandLine)
+	variant := variantFlag(flag.CommandLine)
+	enhc, obo := holeCardFlags(flag.CommandLine)
+	charlie := charlieFlag(flag.CommandLine)
+	bots := flag.String("bots", "", "comma-separated bots to seat after you: "+strings.Join(data.BotNames(), ", "))
+	botBankroll := flag.Int("bot-bankroll", 10000, "starting bankroll of each bot")
+	progression := flag.String("progression", "", "bet progression behind the suggested bet, from the table minimum: "+strings.Join(data.ProgressionNames(), ", "))
+	pairsPays := flag.String("pp-pays", "", "Perfect Pairs paytable for mixed, colored and perfect pairs, e.g. 6,12,25")
+	twentyOnePays := flag.String("21plus3-pays", "", "21+3 paytable for flush, straight, trips, straight flush and suited trips, e.g. 5,10,30,40,100")
+	profilePath := flag.String("profile", defaultProfilePath(), "file that keeps the progressive jackpot between sessions (empty to not keep it)")
+	bustPays := flag.String("bust-pays", "", "dealer bust paytable for 3 to 8+ cards, e.g. 1,2,9,50,100,250")
+	autoplay := tui.DefaultAutoplayConfig()
+	autoBot := flag.String("autoplay", "", "bot that autoplay hands the seat to, instead of the hints: "+strings.Join(data.BotNames(), ", "))
+	flag.IntVar(&autoplay.Rounds, "autoplay-rounds", autoplay.Rounds, "rounds autoplay plays before stopping (0 for no limit)")
+	flag.DurationVar(&autoplay.Speed, "autoplay-speed", autoplay.Speed, "pause between autoplay steps (0 for instant)")
+	flag.IntVar(&autoplay.Target, "autoplay-target", 0, "stop autoplay once the bankroll reaches this")
+	flag.IntVar(&autoplay.LossLimit, "autoplay-loss", 0, "stop autoplay after losing this much")
+	flag.BoolVar(&autoplay.StopOnShuffle, "autoplay-shuffle", false, "stop autoplay when the shoe is due for a reshuffle")
+	flag.Float64Var(&autoplay.TrueCount, "autoplay-tc", 0, "stop autoplay once the true count reaches this (negative counts down)")
+	flag.Parse()
+
+	rules := data.DefaultRules()
+	rules.Variant = *variant
+	rules.NoHoleCard, rules.OriginalBetsOnly = *enhc, *obo
+	rules.Charlie = *charlie
+	players := []data.PlayerConfig{{Name: "You", Bankroll: 500}}
+	if *bots != "" {
+		for i, name := range strings.Split(*bots, ",") {
+			bot, err := data.NewBot(strings.TrimSpace(name), rules, data.HiLo)
+			if err != nil {
+				log.Fatalf("failed to seat bot: %v", err)
+			}
+			players = append(players, data.PlayerConfig{
+				Name:     fmt.Sprintf("Seat %d (%s)", i+2, strings.TrimSpace(name)),
+				Bankroll: *botBankroll,
+				Agent:    bot,
+			})
+		}
+	}
+	game, err := data.NewGameWithRules(rules, players)
 	if err != nil {
 		log.Fatalf("failed to initialize game: %v", err)
 	}
+	for bet, pays := range map[data.SideBet]string{data.SidePerfectPairs: *pairsPays, data.Side21Plus3: *twentyOnePays, data.SideDealerBust: *bustPays} {
+		if pays == "" {
+			continue
+		}
+		table, err := data.ParsePaytable(bet, pays)
+		if err != nil {
+			log.Fatalf("failed to configure side bets: %v", err)
+		}
+		game.SetPaytable(bet, table)
+	}
+	profile, err := loadProfile(*profilePath)
+	if err != nil {
+		log.Fatalf("failed to load profile: %v", err)
+	}
+	game.SetJackpot(&profile.Jackpot)
+
+	model := tui.New(game)
+	switch {
+	case !*deviations:
+		model.UseIndexTable(nil)
+	case *indices != "":
+		table, err := loadIndexTable(*indices, game.Counter().System())
+		if err != nil {
+			log.Fatalf("failed to load index table: %v", err)
+		}
+		model.UseIndexTable(table)
+	}
 
-	program := tea.NewProgram(tui.New(game), tea.WithAltScreen())
+	betAdvisor, err := bets(game.Rules())
+	if err != nil {
+		log.Fatalf("failed to configure betting: %v", err)
+	}
+	if betAdvisor != nil {
+		model.UseBetAdvisor(betAdvisor)
+	}
+	if *progression != "" {
+		p, err := data.NewProgression(*progression, game.Rules().MinBet)
+		if err != nil {
+			log.Fatalf("failed to configure betting: %v", err)
+		}
+		model.UseProgression(p)
+	}
+	if *autoBot != "" {
+		if autoplay.Agent, err = data.NewBot(*autoBot, game.Rules(), game.Counter().System()); err != nil {
+			log.Fatalf("failed to configure autoplay: %v", err)
+		}
+	}
+	model.UseAutoplay(autoplay)
+
+	program := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := program.Run(); err != nil {
 		log.Fatalf("error running TUI: %v", err)
 	}
+	if err := saveProfile(*profilePath, profile); err != nil {
+		log.Fatalf("failed to save profile: %v", err)
+	}
+}
+
+func defaultProfilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "blackjack", "profile.json")
+}
+
+// loadProfile reads the profile at path, starting a fresh one when there is
+// no file yet or no path.
+func loadProfile(path string) (*data.Profile, error) {
+	if path == "" {
+		return data.NewProfile(), nil
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return data.NewProfile(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return data.LoadProfile(file)
+}
+
+func saveProfile(path string, profile *data.Profile) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := profile.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func runDrill(args []string) {
+	fs := flag.NewFlagSet("drill", flag.ExitOnError)
+	mode := fs.String("mode", "countdown", "drill to run: countdown, flash or truecount")
+	decks := fs.Int("decks", 1, "number of decks in the drill shoe")
+	speed := fs.Duration("speed", 500*time.Millisecond, "time each card or flash stays on screen")
+	seats := fs.Int("seats", 0, "flash a full table with this many seats instead of pairs")
+	questions := fs.Int("questions", 10, "number of true count questions per drill")
+	system := fs.String("system", "hilo", "counting system to check answers against")
+	fs.Parse(args)
+
+	decksSet := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "decks" {
+			decksSet = true
+		}
+	})
+
+	cfg := tui.DrillConfig{
+		Decks:     *decks,
+		Speed:     *speed,
+		Seats:     *seats,
+		Questions: *questions,
+	}
+	switch *mode {
+	case "countdown":
+		cfg.Mode = tui.DrillCountdown
+	case "flash":
+		cfg.Mode = tui.DrillFlash
+	case "truecount":
+		cfg.Mode = tui.DrillTrueCount
+		if !decksSet {
+			cfg.Decks = 6
+		}
+	default:
+		log.Fatalf("unknown drill mode %q", *mode)
+	}
+	countSystem, err := data.LookupCountSystem(*system)
+	if err != nil {
+		log.Fatalf("failed to start drill: %v", err)
+	}
+	cfg.System = countSystem
+
+	program := tea.NewProgram(tui.NewDrill(cfg), tea.WithAltScreen())
+	if _, err := program.Run(); err != nil {
+		log.Fatalf("error running drill: %v", err)
+	}
+}
+
+func runIndices(args []string) {
+	fs := flag.NewFlagSet("indices", flag.ExitOnError)
+	system := fs.String("system", "hilo", "counting system whose built-in index table to print")
+	fs.Parse(args)
+
+	countSystem, err := data.LookupCountSystem(*system)
+	if err != nil {
+		log.Fatalf("failed to print index table: %v", err)
+	}
+	table, err := data.DefaultIndexTable(countSystem)
+	if err != nil {
+		log.Fatalf("failed to print index table: %v", err)
+	}
+	if err := table.Save(os.Stdout); err != nil {
+		log.Fatalf("failed to print index table: %v", err)
+	}
+}
+
+func loadIndexTable(path string, system data.CountSystem) (*data.IndexTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return data.LoadIndexTable(file, system)
+}
+
+func runSim(args []string) {
+	fs := flag.NewFlagSet("sim", flag.ExitOnError)
+	rules := rulesFlags(fs)
+	bets := betFlags(fs)
+	rounds := fs.Int("rounds", 1000000, "number of rounds to play")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of parallel workers")
+	seed := fs.Int64("seed", 1, "base shuffle seed; worker i uses seed+i")
+	strategy := fs.String("strategy", "basic", "playing strategy: basic or deviations")
+	indices := fs.String("indices", "", "JSON index table for the deviations strategy")
+	bankroll := fs.Int("bankroll", 10000, "bankroll used to size Kelly bets")
+	fast := fs.Bool("fast", true, "use the allocation-free engine instead of data.Game")
+	system := fs.String("system", "hilo", "counting system that drives bets and index plays")
+	bots := fs.String("bot", "", "comma-separated bots to simulate in turn instead of -strategy and bet flags: "+strings.Join(data.BotNames(), ", "))
+	progression := fs.String("progression", "", "bet progression from the table minimum instead of the bet flags: "+strings.Join(data.ProgressionNames(), ", "))
+	fs.Parse(args)
+
+	cfg := sim.Config{
+		Rules:    rules(),
+		Rounds:   *rounds,
+		Workers:  *workers,
+		Seed:     *seed,
+		Bankroll: *bankroll,
+		Fast:     *fast,
+	}
+	countSystem, err := data.LookupCountSystem(*system)
+	if err != nil {
+		log.Fatalf("failed to configure count: %v", err)
+	}
+	cfg.System = countSystem
+	advisor, err := strategyAdvisor(cfg.Rules, countSystem, *strategy, *indices)
+	if err != nil {
+		log.Fatalf("failed to configure strategy: %v", err)
+	}
+	cfg.Strategy = advisor
+	if cfg.Bets, err = bets(cfg.Rules); err != nil {
+		log.Fatalf("failed to configure betting: %v", err)
+	}
+	if *progression != "" {
+		if cfg.Progression, err = progressionFactory(*progression, cfg.Rules.MinBet); err != nil {
+			log.Fatalf("failed to configure betting: %v", err)
+		}
+	}
+
+	if *bots == "" {
+		result, err := sim.Run(cfg)
+		if err != nil {
+			log.Fatalf("simulation failed: %v", err)
+		}
+		result.Report(os.Stdout, cfg)
+		return
+	}
+	for i, name := range strings.Split(*bots, ",") {
+		if cfg.Agent, err = data.NewBot(strings.TrimSpace(name), cfg.Rules, countSystem); err != nil {
+			log.Fatalf("failed to configure bot: %v", err)
+		}
+		result, err := sim.Run(cfg)
+		if err != nil {
+			log.Fatalf("simulation failed: %v", err)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		result.Report(os.Stdout, cfg)
+	}
+}
+
+func runRor(args []string) {
+	fs := flag.NewFlagSet("ror", flag.ExitOnError)
+	rules := rulesFlags(fs)
+	bets := betFlags(fs)
+	bankroll := fs.Int("bankroll", 10000, "starting bankroll in dollars")
+	system := fs.String("system", "hilo", "counting system that drives bets and index plays")
+	strategy := fs.String("strategy", "deviations", "playing strategy: basic or deviations")
+	indices := fs.String("indices", "", "JSON index table for the deviations strategy")
+	rounds := fs.Int("rounds", 2000000, "rounds simulated to measure win rate and deviation")
+	trials := fs.Int("trials", 500, "bankrolls simulated to check the trip risk of ruin")
+	trip := fs.Int("trip", 20000, "rounds each simulated bankroll plays")
+	perHour := fs.Float64("per-hour", 100, "rounds played per hour")
+	target := fs.Float64("target", 0.05, "risk of ruin to size the required bankroll for")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of parallel workers")
+	seed := fs.Int64("seed", 1, "base shuffle seed")
+	fs.Parse(args)
+
+	if *target <= 0 || *target >= 1 {
+		log.Fatalf("target risk of ruin must be between 0 and 1")
+	}
+	cfg := sim.Config{
+		Rules:    rules(),
+		Rounds:   *rounds,
+		Workers:  *workers,
+		Seed:     *seed,
+		Bankroll: *bankroll,
+		Fast:     true,
+	}
+	countSystem, err := data.LookupCountSystem(*system)
+	if err != nil {
+		log.Fatalf("failed to configure count: %v", err)
+	}
+	cfg.System = countSystem
+	if cfg.Strategy, err = strategyAdvisor(cfg.Rules, countSystem, *strategy, *indices); err != nil {
+		log.Fatalf("failed to configure strategy: %v", err)
+	}
+	if cfg.Bets, err = bets(cfg.Rules); err != nil {
+		log.Fatalf("failed to configure betting: %v", err)
+	}
+	if cfg.Bets == nil {
+		cfg.Bets = data.NewBetAdvisor(cfg.Rules)
+	}
+
+	result, err := sim.Run(cfg)
+	if err != nil {
+		log.Fatalf("simulation failed: %v", err)
+	}
+	ruin, err := sim.SimulateRuin(cfg, *trip, *trials)
+	if err != nil {
+		log.Fatalf("ruin simulation failed: %v", err)
+	}
+	sim.RiskReport{
+		Config:        cfg,
+		Result:        result,
+		Ruin:          ruin,
+		TripRounds:    *trip,
+		RoundsPerHour: *perHour,
+		TargetRoR:     *target,
+	}.Report(os.Stdout)
+}
+
+func runSessions(args []string) {
+	fs := flag.NewFlagSet("sessions", flag.ExitOnError)
+	rules := rulesFlags(fs)
+	progressions := fs.String("progressions", strings.Join(data.ProgressionNames(), ","), "comma-separated bet progressions to compare")
+	unit := fs.Int("unit", 0, "progression unit in dollars (defaults to the table minimum)")
+	bankroll := fs.Int("bankroll", 1000, "bankroll each session starts with")
+	rounds := fs.Int("rounds", 200, "rounds per session")
+	sessions := fs.Int("sessions", 10000, "number of sessions per progression")
+	strategy := fs.String("strategy", "basic", "playing strategy: basic or deviations")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of parallel workers")
+	seed := fs.Int64("seed", 1, "base shuffle seed; session i uses seed+i")
+	fs.Parse(args)
+
+	cfg := sim.Config{
+		Rules:    rules(),
+		Workers:  *workers,
+		Seed:     *seed,
+		Bankroll: *bankroll,
+	}
+	var err error
+	if cfg.Strategy, err = strategyAdvisor(cfg.Rules, data.HiLo, *strategy, ""); err != nil {
+		log.Fatalf("failed to configure strategy: %v", err)
+	}
+	if *unit <= 0 {
+		*unit = cfg.Rules.MinBet
+	}
+
+	var results []sim.SessionResult
+	for _, name := range strings.Split(*progressions, ",") {
+		if cfg.Progression, err = progressionFactory(strings.TrimSpace(name), *unit); err != nil {
+			log.Fatalf("failed to configure betting: %v", err)
+		}
+		result, err := sim.SimulateSessions(cfg, *rounds, *sessions)
+		if err != nil {
+			log.Fatalf("session simulation failed: %v", err)
+		}
+		results = append(results, result)
+	}
+	sim.ReportSessions(os.Stdout, cfg, results)
+}
+
+// runBust measures the dealer bust side bet at each true count.
+func runBust(args []string) {
+	fs := flag.NewFlagSet("bust", flag.ExitOnError)
+	rules := rulesFlags(fs)
+	bets := betFlags(fs)
+	pays := fs.String("pays", "", "paytable for 3 to 8+ card busts (defaults to 1,2,9,50,100,250)")
+	rounds := fs.Int("rounds", 2000000, "number of rounds to play")
+	strategy := fs.String("strategy", "basic", "playing strategy: basic or deviations")
+	system := fs.String("system", "hilo", "counting system that sorts rounds by true count")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of parallel workers")
+	seed := fs.Int64("seed", 1, "base shuffle seed; worker i uses seed+i")
+	fs.Parse(args)
+
+	cfg := sim.Config{
+		Rules:   rules(),
+		Rounds:  *rounds,
+		Workers: *workers,
+		Seed:    *seed,
+	}
+	var err error
+	if cfg.System, err = data.LookupCountSystem(*system); err != nil {
+		log.Fatalf("failed to configure count: %v", err)
+	}
+	if cfg.Strategy, err = strategyAdvisor(cfg.Rules, cfg.System, *strategy, ""); err != nil {
+		log.Fatalf("failed to configure strategy: %v", err)
+	}
+	if cfg.Bets, err = bets(cfg.Rules); err != nil {
+		log.Fatalf("failed to configure betting: %v", err)
+	}
+	table := data.DefaultPaytable(data.SideDealerBust)
+	if *pays != "" {
+		if table, err = data.ParsePaytable(data.SideDealerBust, *pays); err != nil {
+			log.Fatalf("failed to configure side bet: %v", err)
+		}
+	}
+
+	result, err := sim.SimulateDealerBust(cfg, table)
+	if err != nil {
+		log.Fatalf("simulation failed: %v", err)
+	}
+	sim.ReportDealerBust(os.Stdout, cfg, result)
+}
+
+// progressionFactory checks name once and returns a constructor for fresh
+// copies of the progression.
+func progressionFactory(name string, unit int) (func() data.Progression, error) {
+	if _, err := data.NewProgression(name, unit); err != nil {
+		return nil, err
+	}
+	return func() data.Progression {
+		p, _ := data.NewProgression(name, unit)
+		return p
+	}, nil
+}
+
+// runCompare plays side A from args and side B from args followed by the
+// -vs flags, so B only needs to name what it changes.
+func runCompare(args []string) {
+	a, vs, shoes, limit := compareConfig("compare", args)
+	if vs == "" {
+		log.Fatalf("give side B's changes with -vs, e.g. -vs \"-strategy deviations\"")
+	}
+	b, _, _, _ := compareConfig("compare -vs", append(append([]string(nil), args...), strings.Fields(vs)...))
+	comparison, err := sim.Compare(a, b, shoes)
+	if err != nil {
+		log.Fatalf("comparison failed: %v", err)
+	}
+	comparison.Report(os.Stdout, limit)
+}
+
+func compareConfig(name string, args []string) (sim.Config, string, int, int) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	rules := rulesFlags(fs)
+	bets := betFlags(fs)
+	strategy := fs.String("strategy", "basic", "playing strategy: basic or deviations")
+	indices := fs.String("indices", "", "JSON index table for the deviations strategy")
+	system := fs.String("system", "hilo", "counting system that drives bets and index plays")
+	vs := fs.String("vs", "", "flags that side B changes, e.g. \"-strategy deviations\" or \"-s17\"")
+	shoes := fs.Int("shoes", 20000, "number of shoes both sides play")
+	limit := fs.Int("top", 20, "number of differing situations to list (0 for all)")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of parallel workers")
+	seed := fs.Int64("seed", 1, "base shuffle seed; shoe i uses seed+i")
+	bankroll := fs.Int("bankroll", 10000, "bankroll used to size Kelly bets")
+	fs.Parse(args)
+
+	cfg := sim.Config{
+		Rules:    rules(),
+		Workers:  *workers,
+		Seed:     *seed,
+		Bankroll: *bankroll,
+	}
+	countSystem, err := data.LookupCountSystem(*system)
+	if err != nil {
+		log.Fatalf("failed to configure count: %v", err)
+	}
+	cfg.System = countSystem
+	if cfg.Strategy, err = strategyAdvisor(cfg.Rules, countSystem, *strategy, *indices); err != nil {
+		log.Fatalf("failed to configure strategy: %v", err)
+	}
+	if cfg.Bets, err = bets(cfg.Rules); err != nil {
+		log.Fatalf("failed to configure betting: %v", err)
+	}
+	return cfg, *vs, *shoes, *limit
+}
+
+func runEdge(args []string) {
+	fs := flag.NewFlagSet("edge", flag.ExitOnError)
+	rules := rulesFlags(fs)
+	fs.Parse(args)
+
+	if err := analysis.Report(os.Stdout, rules()); err != nil {
+		log.Fatalf("failed to compute house edge: %v", err)
+	}
+}
+
+func runDealer(args []string) {
+	fs := flag.NewFlagSet("dealer", flag.ExitOnError)
+	rules := rulesFlags(fs)
+	peek := fs.Bool("peek", false, "condition on the dealer having peeked and found no blackjack")
+	fs.Parse(args)
+
+	if err := analysis.ReportDealer(os.Stdout, rules(), *peek); err != nil {
+		log.Fatalf("failed to compute dealer outcomes: %v", err)
+	}
+}
+
+// rulesFlags registers the table rule flags on fs and returns a function
+// that builds the Rules once fs has been parsed.
+func rulesFlags(fs *flag.FlagSet) func() data.Rules {
+	defaults := data.DefaultRules()
+	variant := variantFlag(fs)
+	decks := fs.Int("decks", defaults.Decks, "number of decks in the shoe")
+	s17 := fs.Bool("s17", !defaults.DealerHitsSoft17, "dealer stands on soft 17")
+	das := fs.Bool("das", defaults.DoubleAfterSplit, "allow doubling after a split")
+	hsa := fs.Bool("hsa", defaults.HitSplitAces, "let split aces draw, double and resplit instead of taking one card")
+	surrender := fs.Bool("surrender", defaults.Surrender, "offer late surrender")
+	enhc, obo := holeCardFlags(fs)
+	charlie := charlieFlag(fs)
+	maxHands := fs.Int("max-hands", defaults.MaxSplitHands, "maximum hands a player may split to")
+	minBet := fs.Int("min-bet", defaults.MinBet, "table minimum bet")
+	maxBet := fs.Int("max-bet", defaults.MaxBet, "table maximum bet (0 for no limit)")
+	penetration := fs.Float64("pen", defaults.Penetration, "fraction of the shoe dealt before reshuffling")
+	return func() data.Rules {
+		return data.Rules{
+			Variant:          *variant,
+			Decks:            *decks,
+			DealerHitsSoft17: !*s17,
+			DoubleAfterSplit: *das,
+			HitSplitAces:     *hsa,
+			Surrender:        *surrender,
+			NoHoleCard:       *enhc,
+			OriginalBetsOnly: *obo,
+			Charlie:          *charlie,
+			MaxSplitHands:    *maxHands,
+			MinBet:           *minBet,
+			MaxBet:           *maxBet,
+			Penetration:      *penetration,
+		}
+	}
+}
+
+// variantFlag registers the -variant flag on fs.
+func variantFlag(fs *flag.FlagSet) *data.Variant {
+	variant := new(data.Variant)
+	fs.Func("variant", "game to deal: "+strings.Join(data.VariantNames(), ", ")+" (default standard)", func(s string) error {
+		v, err := data.ParseVariant(s)
+		*variant = v
+		return err
+	})
+	return variant
+}
+
+// holeCardFlags registers the European no-hole-card flags on fs.
+func holeCardFlags(fs *flag.FlagSet) (enhc, obo *bool) {
+	enhc = fs.Bool("enhc", false, "deal the dealer no hole card until the players finish")
+	obo = fs.Bool("obo", false, "with -enhc, a dealer blackjack takes only the original bets")
+	return enhc, obo
+}
+
+// charlieFlag registers the -charlie flag on fs.
+func charlieFlag(fs *flag.FlagSet) *int {
+	return fs.Int("charlie", 0, "cards that win a hand outright without busting (0 for no Charlie)")
+}
+
+// betFlags registers the betting flags on fs. The returned function yields
+// nil when no betting option was given, meaning flat table-minimum bets.
+func betFlags(fs *flag.FlagSet) func(data.Rules) (*data.BetAdvisor, error) {
+	ramp := fs.String("ramp", "", "betting ramp as tc:units pairs, e.g. 1:1,2:2,3:4,4:8")
+	unit := fs.Int("unit", 0, "betting unit in dollars (defaults to the table minimum)")
+	kelly := fs.Float64("kelly", 0, "size bets with this fraction of Kelly instead of a ramp")
+	return func(rules data.Rules) (*data.BetAdvisor, error) {
+		if *ramp == "" && *kelly <= 0 && *unit <= 0 {
+			return nil, nil
+		}
+		advisor := data.NewBetAdvisor(rules)
+		if *unit > 0 {
+			advisor.Unit = *unit
+		}
+		switch {
+		case *kelly > 0:
+			if _, ok := rules.EstimatedHouseEdge(); !ok {
+				return nil, fmt.Errorf("%s has no published house edge to size Kelly bets from", rules.Variant)
+			}
+			advisor.Ramp = nil
+			advisor.Kelly.Fraction = *kelly
+		case *ramp != "":
+			parsed, err := data.ParseRamp(*ramp)
+			if err != nil {
+				return nil, err
+			}
+			advisor.Ramp = &parsed
+		}
+		return advisor, nil
+	}
+}
+
+func strategyAdvisor(rules data.Rules, system data.CountSystem, name, indices string) (*data.Advisor, error) {
+	advisor := data.NewAdvisor(rules)
+	switch name {
+	case "basic":
+		return advisor, nil
+	case "deviations":
+		if indices != "" {
+			table, err := loadIndexTable(indices, system)
+			if err != nil {
+				return nil, err
+			}
+			advisor.Deviations = table
+			return advisor, nil
+		}
+		table, err := data.DefaultIndexTable(system)
+		if err != nil {
+			return nil, err
+		}
+		advisor.Deviations = table
+		return advisor, nil
+	default:
+		return nil, fmt.Errorf("unknown strategy %q (want basic or deviations)", name)
+	}
 }